@@ -11,8 +11,13 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/tohjustin/kube-lineage/internal/version"
+	"github.com/tohjustin/kube-lineage/pkg/cmd/access"
 	"github.com/tohjustin/kube-lineage/pkg/cmd/helm"
 	"github.com/tohjustin/kube-lineage/pkg/cmd/lineage"
+	"github.com/tohjustin/kube-lineage/pkg/cmd/lint"
+	"github.com/tohjustin/kube-lineage/pkg/cmd/orphans"
+	"github.com/tohjustin/kube-lineage/pkg/cmd/owners"
+	"github.com/tohjustin/kube-lineage/pkg/cmd/path"
 )
 
 var rootCmdName = "kube-lineage"
@@ -27,7 +32,12 @@ func init() {
 
 func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd := lineage.NewCmd(streams, rootCmdName, "")
+	cmd.AddCommand(access.NewCmd(streams, "", rootCmdName))
 	cmd.AddCommand(helm.NewCmd(streams, "", rootCmdName))
+	cmd.AddCommand(lint.NewCmd(streams, "", rootCmdName))
+	cmd.AddCommand(orphans.NewCmd(streams, "", rootCmdName))
+	cmd.AddCommand(owners.NewCmd(streams, "", rootCmdName))
+	cmd.AddCommand(path.NewCmd(streams, "", rootCmdName))
 	cmd.SetVersionTemplate("{{printf \"%s\" .Version}}\n")
 	cmd.Version = fmt.Sprintf("%#v", version.Get())
 	return cmd
@@ -75,6 +75,12 @@ func (c *client) IsReachable() error {
 }
 
 func (c *client) ResolveAPIResource(s string) (*APIResource, error) {
+	return resolveAPIResource(c.mapper, s)
+}
+
+// resolveAPIResource resolves the provided resource type string into an API
+// resource using the provided RESTMapper.
+func resolveAPIResource(mapper meta.RESTMapper, s string) (*APIResource, error) {
 	var gvr schema.GroupVersionResource
 	var gvk schema.GroupVersionKind
 	var err error
@@ -82,10 +88,10 @@ func (c *client) ResolveAPIResource(s string) (*APIResource, error) {
 	// Resolve type string into GVR
 	fullySpecifiedGVR, gr := schema.ParseResourceArg(strings.ToLower(s))
 	if fullySpecifiedGVR != nil {
-		gvr, _ = c.mapper.ResourceFor(*fullySpecifiedGVR)
+		gvr, _ = mapper.ResourceFor(*fullySpecifiedGVR)
 	}
 	if gvr.Empty() {
-		gvr, err = c.mapper.ResourceFor(gr.WithVersion(""))
+		gvr, err = mapper.ResourceFor(gr.WithVersion(""))
 		if err != nil {
 			if len(gr.Group) == 0 {
 				err = fmt.Errorf("the server doesn't have a resource type \"%s\"", gr.Resource)
@@ -96,7 +102,7 @@ func (c *client) ResolveAPIResource(s string) (*APIResource, error) {
 		}
 	}
 	// Obtain Kind from GVR
-	gvk, err = c.mapper.KindFor(gvr)
+	gvk, err = mapper.KindFor(gvr)
 	if gvk.Empty() {
 		if err != nil {
 			if len(gvr.Group) == 0 {
@@ -108,7 +114,7 @@ func (c *client) ResolveAPIResource(s string) (*APIResource, error) {
 		}
 	}
 	// Determine scope of resource
-	mapping, err := c.mapper.RESTMapping(gvk.GroupKind())
+	mapping, err := mapper.RESTMapping(gvk.GroupKind())
 	if err != nil {
 		if len(gvk.Group) == 0 {
 			err = fmt.Errorf("the server couldn't identify a group kind for resource type \"%s\"", gvk.Kind)
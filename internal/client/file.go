@@ -0,0 +1,351 @@
+package client
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+)
+
+// fileClient is a client that serves objects read from manifest files instead
+// of a Kubernetes cluster.
+type fileClient struct {
+	apis    []APIResource
+	mapper  meta.RESTMapper
+	objects []unstructuredv1.Unstructured
+}
+
+// NewFromFiles returns a client that serves objects read from the provided
+// list of files or directories. Supported files are YAML or JSON manifests
+// (including lists of objects) & tar archives of manifests, optionally gzipped.
+// Namespaced objects without a namespace are placed in the provided namespace.
+func NewFromFiles(paths []string, namespace string) (Interface, error) {
+	objects, err := ReadObjectsFromFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	apis := getObjectsAPIResources(objects)
+	mapper := newStaticRESTMapper(apis)
+	for ix := range objects {
+		o := &objects[ix]
+		gvk := o.GroupVersionKind()
+		m, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, err
+		}
+		if m.Scope.Name() == meta.RESTScopeNameNamespace && len(o.GetNamespace()) == 0 {
+			o.SetNamespace(namespace)
+		}
+		// Objects that were never created on a cluster don't have an UID, so we
+		// generate one that is stable across multiple runs
+		if len(o.GetUID()) == 0 {
+			key := strings.Join([]string{gvk.Group, gvk.Kind, o.GetNamespace(), o.GetName()}, "/")
+			o.SetUID(types.UID(uuid.NewSHA1(uuid.NameSpaceURL, []byte(key)).String()))
+		}
+	}
+
+	c := &fileClient{
+		apis:    apis,
+		mapper:  mapper,
+		objects: objects,
+	}
+	return c, nil
+}
+
+// ReadObjectsFromFiles reads all objects from the provided list of files or
+// directories, which are read the same way as in NewFromFiles.
+func ReadObjectsFromFiles(paths []string) ([]unstructuredv1.Unstructured, error) {
+	var objects []unstructuredv1.Unstructured
+	for _, p := range paths {
+		objs, err := readObjectsFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, objs...)
+	}
+	klog.V(4).Infof("Read %d objects from %d path(s)", len(objects), len(paths))
+	return objects, nil
+}
+
+func (c *fileClient) GetMapper() meta.RESTMapper {
+	return c.mapper
+}
+
+// IsReachable always succeeds as all objects are already read from files.
+func (c *fileClient) IsReachable() error {
+	return nil
+}
+
+func (c *fileClient) ResolveAPIResource(s string) (*APIResource, error) {
+	return resolveAPIResource(c.mapper, expandShortName(c.apis, s))
+}
+
+// Get returns an object that matches the provided name & options.
+func (c *fileClient) Get(_ context.Context, name string, opts GetOptions) (*unstructuredv1.Unstructured, error) {
+	klog.V(4).Infof("Get \"%s\" with options: %+v", name, opts)
+	gk := opts.APIResource.GroupKind()
+	for ix := range c.objects {
+		o := &c.objects[ix]
+		if o.GroupVersionKind().GroupKind() != gk || o.GetName() != name {
+			continue
+		}
+		if opts.APIResource.Namespaced && o.GetNamespace() != opts.Namespace {
+			continue
+		}
+		return o, nil
+	}
+	gr := schema.GroupResource{Group: opts.APIResource.Group, Resource: opts.APIResource.Name}
+	return nil, apierrors.NewNotFound(gr, name)
+}
+
+// GetAPIResources returns the preferred version of all API resources known to
+// the client.
+func (c *fileClient) GetAPIResources(_ context.Context) ([]APIResource, error) {
+	apis := []APIResource{}
+	gkSet := map[schema.GroupKind]struct{}{}
+	for _, api := range c.apis {
+		if _, ok := gkSet[api.GroupKind()]; ok {
+			continue
+		}
+		gkSet[api.GroupKind()] = struct{}{}
+		apis = append(apis, api)
+	}
+	return apis, nil
+}
+
+// GetTable returns a table which contains the name & age of the list of
+// objects that matches the provided options, similar to how the server prints
+// objects of custom resources without any additional printer columns.
+func (c *fileClient) GetTable(ctx context.Context, opts GetTableOptions) (*metav1.Table, error) {
+	klog.V(4).Infof("GetTable with options: %+v", opts)
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Age", Type: "string"},
+		},
+	}
+	for _, name := range opts.Names {
+		o, err := c.Get(ctx, name, GetOptions{APIResource: opts.APIResource, Namespace: opts.Namespace})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		age := "<unknown>"
+		if ts := o.GetCreationTimestamp(); !ts.IsZero() {
+			age = duration.HumanDuration(metav1.Now().Sub(ts.Time))
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  []interface{}{o.GetName(), age},
+			Object: runtime.RawExtension{Object: o.DeepCopy()},
+		})
+	}
+	return table, nil
+}
+
+// List returns a list of objects that matches the provided options. Similar to
+// listing objects on the server, all cluster-scoped objects are included
+// regardless of the provided namespaces.
+func (c *fileClient) List(_ context.Context, opts ListOptions) (*unstructuredv1.UnstructuredList, error) {
+	klog.V(4).Infof("List with options: %+v", opts)
+	includeGKSet := ResourcesToGroupKindSet(opts.APIResourcesToInclude)
+	excludeGKSet := ResourcesToGroupKindSet(opts.APIResourcesToExclude)
+	isClusterScopeRequest, nsSet := len(opts.Namespaces) == 0, map[string]struct{}{}
+	for _, ns := range opts.Namespaces {
+		if ns != "" {
+			nsSet[ns] = struct{}{}
+		} else {
+			isClusterScopeRequest = true
+		}
+	}
+
+	var items []unstructuredv1.Unstructured
+	for _, o := range c.objects {
+		gk := o.GroupVersionKind().GroupKind()
+		if _, ok := includeGKSet[gk]; len(includeGKSet) > 0 && !ok {
+			continue
+		}
+		if _, ok := excludeGKSet[gk]; ok {
+			continue
+		}
+		if _, ok := nsSet[o.GetNamespace()]; !isClusterScopeRequest && len(o.GetNamespace()) > 0 && !ok {
+			continue
+		}
+		items = append(items, o)
+	}
+
+	klog.V(4).Infof("Got %4d objects from %d objects read from files", len(items), len(c.objects))
+	return &unstructuredv1.UnstructuredList{Items: items}, nil
+}
+
+// isManifestFile returns true if the provided filename has an extension of a
+// YAML or JSON file.
+func isManifestFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// isTarFile returns true if the provided filename has an extension of a tar
+// archive, optionally gzipped.
+func isTarFile(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, ".tar") || strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+// readObjectsFromPath reads all objects from the provided path, which is either
+// a file, a directory or "-" for reading from the standard input. Directories
+// are walked recursively & only files with a known extension are read.
+func readObjectsFromPath(path string) ([]unstructuredv1.Unstructured, error) {
+	if path == "-" {
+		return readObjects(os.Stdin, "<stdin>")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return readObjectsFromFile(path)
+	}
+
+	var objects []unstructuredv1.Unstructured
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || (!isManifestFile(p) && !isTarFile(p)) {
+			return nil
+		}
+		objs, err := readObjectsFromFile(p)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, objs...)
+		return nil
+	})
+	return objects, err
+}
+
+// readObjectsFromFile reads all objects from the provided file.
+func readObjectsFromFile(path string) ([]unstructuredv1.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if isTarFile(path) {
+		return readObjectsFromTar(f, path)
+	}
+	return readObjects(f, path)
+}
+
+// readObjectsFromTar reads all objects from the manifest files within the
+// provided tar archive, which may be gzipped.
+func readObjectsFromTar(r io.Reader, source string) ([]unstructuredv1.Unstructured, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read \"%s\": %w", source, err)
+		}
+		defer gr.Close()
+		r = gr
+	} else {
+		r = br
+	}
+
+	var objects []unstructuredv1.Unstructured
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read \"%s\": %w", source, err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !isManifestFile(hdr.Name) {
+			continue
+		}
+		objs, err := readObjects(tr, source+":"+hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, objs...)
+	}
+	return objects, nil
+}
+
+// readObjects reads all objects from the provided stream of YAML or JSON
+// documents. Lists of objects are flattened into their items.
+func readObjects(r io.Reader, source string) ([]unstructuredv1.Unstructured, error) {
+	var objects []unstructuredv1.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode \"%s\": %w", source, err)
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		objects = append(objects, flattenObjects(unstructuredv1.Unstructured{Object: obj}, source)...)
+	}
+	return objects, nil
+}
+
+// flattenObjects returns the provided object, or the items of the object if it
+// is a list of objects. Objects without an API version or kind are ignored.
+func flattenObjects(o unstructuredv1.Unstructured, source string) []unstructuredv1.Unstructured {
+	if o.IsList() {
+		// Items of typed lists (e.g. "PodList") returned by the API server don't
+		// include their API version & kind, so we derive them from the list
+		itemAPIVersion, itemKind := o.GetAPIVersion(), strings.TrimSuffix(o.GetKind(), "List")
+		var objects []unstructuredv1.Unstructured
+		_ = o.EachListItem(func(item runtime.Object) error {
+			u, ok := item.(*unstructuredv1.Unstructured)
+			if !ok {
+				return nil
+			}
+			if len(u.GetKind()) == 0 && len(itemKind) > 0 {
+				u.SetAPIVersion(itemAPIVersion)
+				u.SetKind(itemKind)
+			}
+			objects = append(objects, flattenObjects(*u, source)...)
+			return nil
+		})
+		return objects
+	}
+	if len(o.GetAPIVersion()) == 0 || len(o.GetKind()) == 0 {
+		klog.V(4).Infof("Ignoring object without apiVersion or kind in \"%s\"", source)
+		return nil
+	}
+	return []unstructuredv1.Unstructured{o}
+}
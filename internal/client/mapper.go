@@ -0,0 +1,226 @@
+package client
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/restmapper"
+)
+
+// builtinAPIResources is the list of API resources built into Kubernetes, used
+// for mapping objects when there's no access to the discovery API of a
+// cluster. The first listed version of each resource is its preferred version.
+//
+//nolint:gochecknoglobals
+var builtinAPIResources = []APIResource{
+	{Group: "", Version: "v1", Kind: "ComponentStatus", Name: "componentstatuses", ShortNames: []string{"cs"}},
+	{Group: "", Version: "v1", Kind: "ConfigMap", Name: "configmaps", ShortNames: []string{"cm"}, Namespaced: true},
+	{Group: "", Version: "v1", Kind: "Endpoints", Name: "endpoints", ShortNames: []string{"ep"}, Namespaced: true},
+	{Group: "", Version: "v1", Kind: "Event", Name: "events", ShortNames: []string{"ev"}, Namespaced: true},
+	{Group: "", Version: "v1", Kind: "LimitRange", Name: "limitranges", ShortNames: []string{"limits"}, Namespaced: true},
+	{Group: "", Version: "v1", Kind: "Namespace", Name: "namespaces", ShortNames: []string{"ns"}},
+	{Group: "", Version: "v1", Kind: "Node", Name: "nodes", ShortNames: []string{"no"}},
+	{Group: "", Version: "v1", Kind: "PersistentVolume", Name: "persistentvolumes", ShortNames: []string{"pv"}},
+	{Group: "", Version: "v1", Kind: "PersistentVolumeClaim", Name: "persistentvolumeclaims", ShortNames: []string{"pvc"}, Namespaced: true},
+	{Group: "", Version: "v1", Kind: "Pod", Name: "pods", ShortNames: []string{"po"}, Namespaced: true},
+	{Group: "", Version: "v1", Kind: "PodTemplate", Name: "podtemplates", Namespaced: true},
+	{Group: "", Version: "v1", Kind: "ReplicationController", Name: "replicationcontrollers", ShortNames: []string{"rc"}, Namespaced: true},
+	{Group: "", Version: "v1", Kind: "ResourceQuota", Name: "resourcequotas", ShortNames: []string{"quota"}, Namespaced: true},
+	{Group: "", Version: "v1", Kind: "Secret", Name: "secrets", Namespaced: true},
+	{Group: "", Version: "v1", Kind: "Service", Name: "services", ShortNames: []string{"svc"}, Namespaced: true},
+	{Group: "", Version: "v1", Kind: "ServiceAccount", Name: "serviceaccounts", ShortNames: []string{"sa"}, Namespaced: true},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration", Name: "mutatingwebhookconfigurations"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration", Name: "validatingwebhookconfigurations"},
+	{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition", Name: "customresourcedefinitions", ShortNames: []string{"crd", "crds"}},
+	{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService", Name: "apiservices"},
+	{Group: "apps", Version: "v1", Kind: "ControllerRevision", Name: "controllerrevisions", Namespaced: true},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet", Name: "daemonsets", ShortNames: []string{"ds"}, Namespaced: true},
+	{Group: "apps", Version: "v1", Kind: "Deployment", Name: "deployments", ShortNames: []string{"deploy"}, Namespaced: true},
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet", Name: "replicasets", ShortNames: []string{"rs"}, Namespaced: true},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet", Name: "statefulsets", ShortNames: []string{"sts"}, Namespaced: true},
+	{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler", Name: "horizontalpodautoscalers", ShortNames: []string{"hpa"}, Namespaced: true},
+	{Group: "batch", Version: "v1", Kind: "CronJob", Name: "cronjobs", ShortNames: []string{"cj"}, Namespaced: true},
+	{Group: "batch", Version: "v1", Kind: "Job", Name: "jobs", Namespaced: true},
+	{Group: "certificates.k8s.io", Version: "v1", Kind: "CertificateSigningRequest", Name: "certificatesigningrequests", ShortNames: []string{"csr"}},
+	{Group: "coordination.k8s.io", Version: "v1", Kind: "Lease", Name: "leases", Namespaced: true},
+	{Group: "discovery.k8s.io", Version: "v1", Kind: "EndpointSlice", Name: "endpointslices", Namespaced: true},
+	{Group: "events.k8s.io", Version: "v1", Kind: "Event", Name: "events", ShortNames: []string{"ev"}, Namespaced: true},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema", Name: "flowschemas"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration", Name: "prioritylevelconfigurations"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress", Name: "ingresses", ShortNames: []string{"ing"}, Namespaced: true},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass", Name: "ingressclasses"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy", Name: "networkpolicies", ShortNames: []string{"netpol"}, Namespaced: true},
+	{Group: "node.k8s.io", Version: "v1", Kind: "RuntimeClass", Name: "runtimeclasses"},
+	{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget", Name: "poddisruptionbudgets", ShortNames: []string{"pdb"}, Namespaced: true},
+	{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy", Name: "podsecuritypolicies", ShortNames: []string{"psp"}},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole", Name: "clusterroles"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding", Name: "clusterrolebindings"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role", Name: "roles", Namespaced: true},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding", Name: "rolebindings", Namespaced: true},
+	{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass", Name: "priorityclasses", ShortNames: []string{"pc"}},
+	{Group: "storage.k8s.io", Version: "v1", Kind: "CSIDriver", Name: "csidrivers"},
+	{Group: "storage.k8s.io", Version: "v1", Kind: "CSINode", Name: "csinodes"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity", Name: "csistoragecapacities", Namespaced: true},
+	{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass", Name: "storageclasses", ShortNames: []string{"sc"}},
+	{Group: "storage.k8s.io", Version: "v1", Kind: "VolumeAttachment", Name: "volumeattachments"},
+}
+
+// getCRDAPIResources returns the list of API resources defined by the provided
+// CustomResourceDefinition object.
+func getCRDAPIResources(crd unstructuredv1.Unstructured) []APIResource {
+	group, _, _ := unstructuredv1.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructuredv1.NestedString(crd.Object, "spec", "names", "kind")
+	plural, _, _ := unstructuredv1.NestedString(crd.Object, "spec", "names", "plural")
+	singular, _, _ := unstructuredv1.NestedString(crd.Object, "spec", "names", "singular")
+	shortNames, _, _ := unstructuredv1.NestedStringSlice(crd.Object, "spec", "names", "shortNames")
+	scope, _, _ := unstructuredv1.NestedString(crd.Object, "spec", "scope")
+	versions, _, _ := unstructuredv1.NestedSlice(crd.Object, "spec", "versions")
+	if len(group) == 0 || len(kind) == 0 || len(plural) == 0 {
+		return nil
+	}
+
+	var storageVersion string
+	var servedVersions []string
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructuredv1.NestedString(version, "name")
+		served, _, _ := unstructuredv1.NestedBool(version, "served")
+		storage, _, _ := unstructuredv1.NestedBool(version, "storage")
+		switch {
+		case len(name) == 0 || !served:
+			continue
+		case storage:
+			storageVersion = name
+		default:
+			servedVersions = append(servedVersions, name)
+		}
+	}
+	// Prefer the storage version of the resource
+	if len(storageVersion) > 0 {
+		servedVersions = append([]string{storageVersion}, servedVersions...)
+	}
+
+	apis := make([]APIResource, 0, len(servedVersions))
+	for _, v := range servedVersions {
+		apis = append(apis, APIResource{
+			Group:        group,
+			Version:      v,
+			Kind:         kind,
+			Name:         plural,
+			SingularName: singular,
+			ShortNames:   shortNames,
+			Namespaced:   scope == "Namespaced",
+		})
+	}
+	return apis
+}
+
+// getObjectsAPIResources returns the list of API resources for mapping the
+// provided objects, which consists of the builtin API resources, the API
+// resources defined by CustomResourceDefinition objects & the API resources
+// guessed from the remaining objects.
+func getObjectsAPIResources(objects []unstructuredv1.Unstructured) []APIResource {
+	apis := make([]APIResource, len(builtinAPIResources))
+	copy(apis, builtinAPIResources)
+	for _, o := range objects {
+		gvk := o.GroupVersionKind()
+		if gvk.Group == "apiextensions.k8s.io" && gvk.Kind == "CustomResourceDefinition" {
+			apis = append(apis, getCRDAPIResources(o)...)
+		}
+	}
+
+	// Register any unknown versions & types of objects, assuming that an object
+	// type is namespaced if any of its objects has a namespace
+	apiIxByGVK := map[schema.GroupVersionKind]int{}
+	apiIxByGK := map[schema.GroupKind]int{}
+	guessedGKs := map[schema.GroupKind]struct{}{}
+	for ix := len(apis) - 1; ix >= 0; ix-- {
+		apiIxByGVK[apis[ix].GroupVersionKind()] = ix
+		apiIxByGK[apis[ix].GroupKind()] = ix
+	}
+	for _, o := range objects {
+		gvk := o.GroupVersionKind()
+		if ix, ok := apiIxByGVK[gvk]; ok {
+			if _, ok := guessedGKs[gvk.GroupKind()]; ok && len(o.GetNamespace()) > 0 {
+				apis[ix].Namespaced = true
+			}
+			continue
+		}
+		api := APIResource{
+			Group:      gvk.Group,
+			Version:    gvk.Version,
+			Kind:       gvk.Kind,
+			Namespaced: len(o.GetNamespace()) > 0,
+		}
+		if ix, ok := apiIxByGK[gvk.GroupKind()]; ok {
+			api.Name = apis[ix].Name
+			api.SingularName = apis[ix].SingularName
+			api.ShortNames = apis[ix].ShortNames
+			api.Namespaced = apis[ix].Namespaced
+		} else {
+			plural, singular := meta.UnsafeGuessKindToResource(gvk)
+			api.Name = plural.Resource
+			api.SingularName = singular.Resource
+			apiIxByGK[gvk.GroupKind()] = len(apis)
+			guessedGKs[gvk.GroupKind()] = struct{}{}
+		}
+		apiIxByGVK[gvk] = len(apis)
+		apis = append(apis, api)
+	}
+
+	return apis
+}
+
+// newStaticRESTMapper returns a RESTMapper that maps the provided list of API
+// resources, where the first listed version of each API group is its preferred
+// version.
+func newStaticRESTMapper(apis []APIResource) meta.RESTMapper {
+	var groups []*restmapper.APIGroupResources
+	groupByName := map[string]*restmapper.APIGroupResources{}
+	for _, api := range apis {
+		g, ok := groupByName[api.Group]
+		if !ok {
+			g = &restmapper.APIGroupResources{
+				Group: metav1.APIGroup{
+					Name: api.Group,
+					PreferredVersion: metav1.GroupVersionForDiscovery{
+						GroupVersion: schema.GroupVersion{Group: api.Group, Version: api.Version}.String(),
+						Version:      api.Version,
+					},
+				},
+				VersionedResources: map[string][]metav1.APIResource{},
+			}
+			groupByName[api.Group] = g
+			groups = append(groups, g)
+		}
+		if _, ok := g.VersionedResources[api.Version]; !ok {
+			g.Group.Versions = append(g.Group.Versions, metav1.GroupVersionForDiscovery{
+				GroupVersion: schema.GroupVersion{Group: api.Group, Version: api.Version}.String(),
+				Version:      api.Version,
+			})
+		}
+		g.VersionedResources[api.Version] = append(g.VersionedResources[api.Version], metav1.APIResource(api))
+	}
+	return restmapper.NewDiscoveryRESTMapper(groups)
+}
+
+// expandShortName returns the provided resource type string with its resource
+// name expanded if it is the short name of any of the provided API resources.
+func expandShortName(apis []APIResource, s string) string {
+	tokens := strings.SplitN(strings.ToLower(s), ".", 2)
+	for _, api := range apis {
+		for _, sn := range api.ShortNames {
+			if sn == tokens[0] {
+				tokens[0] = api.Name
+				return strings.Join(tokens, ".")
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,169 @@
+package graph
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ResolveAccess returns a relationship tree of every grant path that allows
+// the provided verb to be performed on the object with the provided UID. Each
+// path starts from the object & goes through the Role or ClusterRole whose
+// policy rules allow the verb, the RoleBinding or ClusterRoleBinding that
+// references the role, the subject of the binding & finally the Pods running as
+// the subject if it's a ServiceAccount.
+//
+// Policy rules of every role in the provided node map are evaluated, including
+// rules that match all API groups or resources. The provided node map should be
+// resolved with synthetic nodes for users & groups included (see
+// ResolveOptions.IncludeSubjects).
+//
+//nolint:funlen,gocognit
+func ResolveAccess(nodeMap NodeMap, uid types.UID, verb string) (NodeMap, error) {
+	target, ok := nodeMap[uid]
+	if !ok {
+		return nil, fmt.Errorf("requested object (uid: %s) not found in list of fetched objects", uid)
+	}
+
+	result := NodeMap{}
+	copyNodeFn := func(n *Node) *Node {
+		if c, ok := result[n.UID]; ok {
+			return c
+		}
+		c := *n
+		c.Dependencies = map[types.UID]RelationshipSet{}
+		c.Dependents = map[types.UID]RelationshipSet{}
+		result[c.UID] = &c
+		return &c
+	}
+	addEdgeFn := func(parent, child *Node, depth uint, rset RelationshipSet) {
+		p, c := copyNodeFn(parent), copyNodeFn(child)
+		if c.Depth == 0 || depth < c.Depth {
+			c.Depth = depth
+		}
+		for r := range rset {
+			p.AddDependent(c.UID, r)
+			c.AddDependency(p.UID, r)
+		}
+	}
+	filterFn := func(rset RelationshipSet, rels ...Relationship) RelationshipSet {
+		filtered := RelationshipSet{}
+		for _, r := range rels {
+			if _, ok := rset[r]; ok {
+				filtered[r] = struct{}{}
+			}
+		}
+		return filtered
+	}
+
+	copyNodeFn(target)
+	for _, role := range nodeMap {
+		if role.Missing || role.Group != rbacv1.GroupName {
+			continue
+		}
+		var roleRels RelationshipSet
+		switch role.Kind {
+		case "ClusterRole":
+			roleRels = RelationshipSet{RelationshipClusterRolePolicyRule: struct{}{}}
+		case "Role":
+			// Roles only grant access to objects in their own namespace
+			if role.Namespace != target.Namespace {
+				continue
+			}
+			roleRels = RelationshipSet{RelationshipRolePolicyRule: struct{}{}}
+		default:
+			continue
+		}
+		rules, err := getPolicyRules(role)
+		if err != nil {
+			return nil, err
+		}
+		if !policyRulesAllow(rules, verb, target) {
+			continue
+		}
+		for bindingUID, rset := range role.Dependents {
+			bindingRels := filterFn(rset, RelationshipClusterRoleBindingRole, RelationshipRoleBindingRole)
+			binding, ok := nodeMap[bindingUID]
+			if len(bindingRels) == 0 || !ok {
+				continue
+			}
+			// RoleBindings only grant access to objects in their own namespace,
+			// even if they reference a ClusterRole
+			if binding.Namespaced && binding.Namespace != target.Namespace {
+				continue
+			}
+			for subjectUID, rset := range binding.Dependents {
+				subjectRels := filterFn(rset, RelationshipClusterRoleBindingSubject, RelationshipRoleBindingSubject)
+				subject, ok := nodeMap[subjectUID]
+				if len(subjectRels) == 0 || !ok {
+					continue
+				}
+				addEdgeFn(target, role, 1, roleRels)
+				addEdgeFn(role, binding, 2, bindingRels)
+				addEdgeFn(binding, subject, 3, subjectRels)
+				if subject.Synthetic {
+					continue
+				}
+				for podUID, rset := range subject.Dependents {
+					podRels := filterFn(rset, RelationshipPodServiceAccount)
+					if pod, ok := nodeMap[podUID]; ok && len(podRels) > 0 {
+						addEdgeFn(subject, pod, 4, podRels)
+					}
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// getPolicyRules returns the policy rules of the provided Role or ClusterRole.
+func getPolicyRules(n *Node) ([]rbacv1.PolicyRule, error) {
+	switch n.Kind {
+	case "ClusterRole":
+		var cr rbacv1.ClusterRole
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.UnstructuredContent(), &cr); err != nil {
+			return nil, err
+		}
+		return getEffectivePolicyRules(n, cr.Rules), nil
+	case "Role":
+		var ro rbacv1.Role
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.UnstructuredContent(), &ro); err != nil {
+			return nil, err
+		}
+		return ro.Rules, nil
+	}
+	return []rbacv1.PolicyRule{}, nil
+}
+
+// policyRulesAllow returns true if any of the provided policy rules allow the
+// provided verb to be performed on the provided object.
+func policyRulesAllow(rules []rbacv1.PolicyRule, verb string, n *Node) bool {
+	for _, r := range rules {
+		if !sets.NewString(r.Verbs...).HasAny(rbacv1.VerbAll, verb) {
+			continue
+		}
+		if policyRuleMatches(r, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyRuleMatches returns true if the provided policy rule matches the
+// provided object, regardless of the rule's verbs.
+func policyRuleMatches(r rbacv1.PolicyRule, n *Node) bool {
+	if !sets.NewString(r.APIGroups...).HasAny(rbacv1.APIGroupAll, n.Group) {
+		return false
+	}
+	if !sets.NewString(r.Resources...).HasAny(rbacv1.ResourceAll, n.Resource) {
+		return false
+	}
+	if len(r.ResourceNames) > 0 && !sets.NewString(r.ResourceNames...).Has(n.Name) {
+		return false
+	}
+	return true
+}
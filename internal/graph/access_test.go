@@ -0,0 +1,314 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestPolicyRulesAllow(t *testing.T) {
+	t.Parallel()
+
+	n := &Node{Group: "", Resource: "secrets", Name: "foo"}
+	tests := []struct {
+		name     string
+		rule     rbacv1.PolicyRule
+		verb     string
+		expected bool
+	}{
+		{
+			name:     "matching verb & resource",
+			rule:     rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}},
+			verb:     "get",
+			expected: true,
+		},
+		{
+			name:     "matching resource name",
+			rule:     rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"bar", "foo"}, Verbs: []string{"get"}},
+			verb:     "get",
+			expected: true,
+		},
+		{
+			name:     "wildcard verb, API group & resource",
+			rule:     rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			verb:     "delete",
+			expected: true,
+		},
+		{
+			name:     "different verb",
+			rule:     rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+			verb:     "delete",
+			expected: false,
+		},
+		{
+			name:     "different API group",
+			rule:     rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+			verb:     "get",
+			expected: false,
+		},
+		{
+			name:     "different resource",
+			rule:     rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+			verb:     "get",
+			expected: false,
+		},
+		{
+			name:     "different resource name",
+			rule:     rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"bar"}, Verbs: []string{"get"}},
+			verb:     "get",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if actual := policyRulesAllow([]rbacv1.PolicyRule{tt.rule}, tt.verb, n); actual != tt.expected {
+				t.Fatalf("expected %t got %t", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolveAccess(t *testing.T) {
+	t.Parallel()
+
+	objects := newTestObjects(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: default
+`, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+  namespace: default
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+  namespace: default
+spec:
+  serviceAccountName: app
+  containers:
+  - name: app
+    image: nginx
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: secret-reader
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: secret-reader
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: secret-reader
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: Group
+  name: ops
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: secret-reader
+  namespace: other
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: secret-reader
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: bob
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: reader
+  namespace: default
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  resourceNames: [foo]
+  verbs: [get, update]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: reader
+  namespace: default
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: reader
+subjects:
+- kind: ServiceAccount
+  name: app
+  namespace: default
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: alice
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: reader
+  namespace: other
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: reader
+  namespace: other
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: reader
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: bob
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: secret-deleter
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  resourceNames: [bar]
+  verbs: [delete]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: secret-deleter
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: secret-deleter
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: Group
+  name: ops
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: unbound
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: cluster-admin
+rules:
+- apiGroups: ["*"]
+  resources: ["*"]
+  verbs: ["*"]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: cluster-admin
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: cluster-admin
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: Group
+  name: system:masters
+`)
+	nodeMap := resolveTestGraph(t, objects, ResolveOptions{IncludeSubjects: true})
+
+	tests := []struct {
+		name     string
+		verb     string
+		expected []string
+	}{
+		{
+			name: "verb allowed by roles & cluster roles",
+			verb: "get",
+			expected: []string{
+				"ClusterRole/cluster-admin -[RoleBindingRole]-> ClusterRoleBinding/cluster-admin",
+				"ClusterRole/secret-reader -[RoleBindingRole]-> ClusterRoleBinding/secret-reader",
+				"ClusterRoleBinding/cluster-admin -[ClusterRoleBindingSubject]-> subject:Group/system:masters",
+				"ClusterRoleBinding/secret-reader -[ClusterRoleBindingSubject]-> subject:Group/ops",
+				"Role/default/reader -[RoleBindingRole]-> RoleBinding/default/reader",
+				"RoleBinding/default/reader -[RoleBindingSubject]-> ServiceAccount/default/app",
+				"RoleBinding/default/reader -[RoleBindingSubject]-> subject:User/alice",
+				"Secret/default/foo -[ClusterRolePolicyRule]-> ClusterRole/cluster-admin",
+				"Secret/default/foo -[ClusterRolePolicyRule]-> ClusterRole/secret-reader",
+				"Secret/default/foo -[RolePolicyRule]-> Role/default/reader",
+				"ServiceAccount/default/app -[PodServiceAccount]-> Pod/default/app",
+			},
+		},
+		{
+			name: "verb allowed by role",
+			verb: "update",
+			expected: []string{
+				"ClusterRole/cluster-admin -[RoleBindingRole]-> ClusterRoleBinding/cluster-admin",
+				"ClusterRoleBinding/cluster-admin -[ClusterRoleBindingSubject]-> subject:Group/system:masters",
+				"Role/default/reader -[RoleBindingRole]-> RoleBinding/default/reader",
+				"RoleBinding/default/reader -[RoleBindingSubject]-> ServiceAccount/default/app",
+				"RoleBinding/default/reader -[RoleBindingSubject]-> subject:User/alice",
+				"Secret/default/foo -[ClusterRolePolicyRule]-> ClusterRole/cluster-admin",
+				"Secret/default/foo -[RolePolicyRule]-> Role/default/reader",
+				"ServiceAccount/default/app -[PodServiceAccount]-> Pod/default/app",
+			},
+		},
+		{
+			name: "verb only allowed by wildcard rules",
+			verb: "delete",
+			expected: []string{
+				"ClusterRole/cluster-admin -[RoleBindingRole]-> ClusterRoleBinding/cluster-admin",
+				"ClusterRoleBinding/cluster-admin -[ClusterRoleBindingSubject]-> subject:Group/system:masters",
+				"Secret/default/foo -[ClusterRolePolicyRule]-> ClusterRole/cluster-admin",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accessMap, err := ResolveAccess(nodeMap, testUID("Secret", "default", "foo"), tt.verb)
+			if err != nil {
+				t.Fatalf("failed to resolve access: %v", err)
+			}
+			if edges := getTestEdges(accessMap); !reflect.DeepEqual(edges, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, edges)
+			}
+		})
+	}
+
+	t.Run("unknown object", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := ResolveAccess(nodeMap, "unknown", "get"); err == nil {
+			t.Fatalf("expected error got nil")
+		}
+	})
+}
@@ -0,0 +1,380 @@
+package graph
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Hardcode "sigs.k8s.io/gateway-api/apis/v1beta1.GroupName" as
+// "gateway.networking.k8s.io" so we don't need import the entire
+// sigs.k8s.io/gateway-api package.
+const GatewayAPIGroupName = "gateway.networking.k8s.io"
+
+const (
+	// Gateway API Gateway & GatewayClass relationships.
+	RelationshipGatewayClass               Relationship = "GatewayClass"
+	RelationshipGatewayClassParameters     Relationship = "GatewayClassParameters"
+	RelationshipGatewayListenerCertificate Relationship = "GatewayListenerCertificate"
+
+	// Gateway API GRPCRoute, HTTPRoute & TLSRoute relationships.
+	RelationshipRouteBackend               Relationship = "RouteBackend"
+	RelationshipRouteCrossNamespaceBackend Relationship = "RouteCrossNamespaceBackend"
+	RelationshipRouteParent                Relationship = "RouteParent"
+
+	// Gateway API ReferenceGrant relationships.
+	RelationshipReferenceGrantFrom Relationship = "ReferenceGrantFrom"
+	RelationshipReferenceGrantTo   Relationship = "ReferenceGrantTo"
+)
+
+// getGatewayClassRelationships returns a map of relationships that this
+// GatewayClass has with other objects, based on what was referenced in its
+// manifest.
+//
+//nolint:unparam
+func getGatewayClassRelationships(n *Node) (*RelationshipMap, error) {
+	result := NewRelationshipMap()
+
+	// RelationshipGatewayClassParameters
+	if m, found, _ := unstructuredv1.NestedMap(n.UnstructuredContent(), "spec", "parametersRef"); found {
+		if ref, ok := getGatewayAPIObjectReference(m, "", ""); ok {
+			result.AddDependencyByKey(ref.Key(), RelationshipGatewayClassParameters)
+		}
+	}
+
+	return &result, nil
+}
+
+// getGatewayRelationships returns a map of relationships that this Gateway
+// has with other objects, based on what was referenced in its manifest.
+//
+//nolint:unparam
+func getGatewayRelationships(n *Node) (*RelationshipMap, error) {
+	var ref ObjectReference
+	ns := n.Namespace
+	result := NewRelationshipMap()
+
+	// RelationshipGatewayClass
+	if gc := n.GetNestedString("spec", "gatewayClassName"); len(gc) != 0 {
+		ref = ObjectReference{Group: GatewayAPIGroupName, Kind: "GatewayClass", Name: gc}
+		result.AddDependencyByKey(ref.Key(), RelationshipGatewayClass)
+	}
+
+	// RelationshipGatewayListenerCertificate
+	listeners, _, _ := unstructuredv1.NestedSlice(n.UnstructuredContent(), "spec", "listeners")
+	for _, l := range listeners {
+		lm, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		certRefs, _, _ := unstructuredv1.NestedSlice(lm, "tls", "certificateRefs")
+		for _, c := range certRefs {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if ref, ok := getGatewayAPIObjectReference(cm, "", "Secret"); ok {
+				if len(ref.Namespace) == 0 {
+					ref.Namespace = ns
+				}
+				result.AddDependencyByKey(ref.Key(), RelationshipGatewayListenerCertificate)
+			}
+		}
+	}
+
+	return &result, nil
+}
+
+// getRouteRelationships returns a map of relationships that this route (ie.
+// GRPCRoute, HTTPRoute or TLSRoute) has with other objects, based on what was
+// referenced in its manifest.
+//
+//nolint:unparam
+func getRouteRelationships(n *Node) (*RelationshipMap, error) {
+	ns := n.Namespace
+	result := NewRelationshipMap()
+
+	// RelationshipRouteParent
+	parentRefs, _, _ := unstructuredv1.NestedSlice(n.UnstructuredContent(), "spec", "parentRefs")
+	for _, p := range parentRefs {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ref, ok := getGatewayAPIObjectReference(pm, GatewayAPIGroupName, "Gateway"); ok {
+			if len(ref.Namespace) == 0 {
+				ref.Namespace = ns
+			}
+			result.AddDependencyByKey(ref.Key(), RelationshipRouteParent)
+		}
+	}
+
+	// RelationshipRouteBackend
+	// RelationshipRouteCrossNamespaceBackend
+	//
+	// Backends are dependencies of the route, the same way as the Services of
+	// an Ingress. Backends in other namespaces are only kept if a
+	// ReferenceGrant in the backend's namespace permits it (see
+	// pruneDisallowedRouteReferences).
+	rules, _, _ := unstructuredv1.NestedSlice(n.UnstructuredContent(), "spec", "rules")
+	for _, r := range rules {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		backendRefs, _, _ := unstructuredv1.NestedSlice(rm, "backendRefs")
+		for _, b := range backendRefs {
+			bm, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref, ok := getGatewayAPIObjectReference(bm, "", "Service")
+			if !ok {
+				continue
+			}
+			switch ref.Namespace {
+			case "", ns:
+				ref.Namespace = ns
+				result.AddDependencyByKey(ref.Key(), RelationshipRouteBackend)
+			default:
+				result.AddDependencyByKey(ref.Key(), RelationshipRouteCrossNamespaceBackend)
+			}
+		}
+	}
+
+	return &result, nil
+}
+
+// getReferenceGrantRelationships returns a map of relationships that this
+// ReferenceGrant has with other objects, based on what was referenced in its
+// manifest.
+//
+//nolint:unparam
+func getReferenceGrantRelationships(n *Node) (*RelationshipMap, error) {
+	ns := n.Namespace
+	result := NewRelationshipMap()
+
+	// RelationshipReferenceGrantFrom
+	from, _, _ := unstructuredv1.NestedSlice(n.UnstructuredContent(), "spec", "from")
+	for _, f := range from {
+		fm, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _, _ := unstructuredv1.NestedString(fm, "group")
+		kind, _, _ := unstructuredv1.NestedString(fm, "kind")
+		fromNS, _, _ := unstructuredv1.NestedString(fm, "namespace")
+		if len(kind) == 0 || len(fromNS) == 0 {
+			continue
+		}
+		os := ObjectSelector{Group: group, Kind: kind, Namespaces: sets.NewString(fromNS)}
+		result.AddDependentBySelector(os, RelationshipReferenceGrantFrom)
+	}
+
+	// RelationshipReferenceGrantTo
+	to, _, _ := unstructuredv1.NestedSlice(n.UnstructuredContent(), "spec", "to")
+	for _, t := range to {
+		tm, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _, _ := unstructuredv1.NestedString(tm, "group")
+		kind, _, _ := unstructuredv1.NestedString(tm, "kind")
+		name, _, _ := unstructuredv1.NestedString(tm, "name")
+		switch {
+		case len(kind) == 0:
+			continue
+		case len(name) != 0:
+			ref := ObjectReference{Group: group, Kind: kind, Name: name, Namespace: ns}
+			result.AddDependencyByKey(ref.Key(), RelationshipReferenceGrantTo)
+		default:
+			os := ObjectSelector{Group: group, Kind: kind, Namespaces: sets.NewString(ns)}
+			result.AddDependencyBySelector(os, RelationshipReferenceGrantTo)
+		}
+	}
+
+	return &result, nil
+}
+
+// getGatewayAPIObjectReference returns the object referenced by the provided
+// Gateway API object reference (eg. ParentReference, BackendObjectReference,
+// SecretObjectReference), using the provided group & kind if they're not
+// specified. The namespace of the returned reference is left empty if it's
+// not specified.
+func getGatewayAPIObjectReference(m map[string]interface{}, defaultGroup, defaultKind string) (ObjectReference, bool) {
+	name, _, _ := unstructuredv1.NestedString(m, "name")
+	if len(name) == 0 {
+		return ObjectReference{}, false
+	}
+	group, found, _ := unstructuredv1.NestedString(m, "group")
+	if !found {
+		group = defaultGroup
+	}
+	kind, _, _ := unstructuredv1.NestedString(m, "kind")
+	if len(kind) == 0 {
+		kind = defaultKind
+	}
+	if len(kind) == 0 {
+		return ObjectReference{}, false
+	}
+	namespace, _, _ := unstructuredv1.NestedString(m, "namespace")
+	return ObjectReference{Group: group, Kind: kind, Name: name, Namespace: namespace}, true
+}
+
+// isRouteKind returns true if the provided GroupKind is a Gateway API route.
+func isRouteKind(group, kind string) bool {
+	if group != GatewayAPIGroupName {
+		return false
+	}
+	switch kind {
+	case "GRPCRoute", "HTTPRoute", "TLSRoute":
+		return true
+	}
+	return false
+}
+
+// referenceGrant contains the references allowed by a ReferenceGrant, from
+// objects in the namespaces listed in "spec.from" to objects in the
+// ReferenceGrant's namespace listed in "spec.to".
+type referenceGrant struct {
+	Namespace string
+	From      []ObjectReference
+	To        []ObjectReference
+}
+
+// allows returns true if the ReferenceGrant permits the provided object to
+// reference the provided target object.
+func (g *referenceGrant) allows(from, to *Node) bool {
+	if g.Namespace != to.Namespace {
+		return false
+	}
+	var fromOK, toOK bool
+	for _, f := range g.From {
+		if f.Group == from.Group && f.Kind == from.Kind && f.Namespace == from.Namespace {
+			fromOK = true
+			break
+		}
+	}
+	for _, t := range g.To {
+		if t.Group == to.Group && t.Kind == to.Kind && (len(t.Name) == 0 || t.Name == to.Name) {
+			toOK = true
+			break
+		}
+	}
+	return fromOK && toOK
+}
+
+// getReferenceGrants returns the references allowed by every ReferenceGrant in
+// the provided node map.
+func getReferenceGrants(nodeMap map[types.UID]*Node) []referenceGrant {
+	var result []referenceGrant
+	for _, n := range nodeMap {
+		if n.Group != GatewayAPIGroupName || n.Kind != "ReferenceGrant" || n.Unstructured == nil {
+			continue
+		}
+		grant := referenceGrant{Namespace: n.Namespace}
+		from, _, _ := unstructuredv1.NestedSlice(n.UnstructuredContent(), "spec", "from")
+		for _, f := range from {
+			if fm, ok := f.(map[string]interface{}); ok {
+				group, _, _ := unstructuredv1.NestedString(fm, "group")
+				kind, _, _ := unstructuredv1.NestedString(fm, "kind")
+				ns, _, _ := unstructuredv1.NestedString(fm, "namespace")
+				grant.From = append(grant.From, ObjectReference{Group: group, Kind: kind, Namespace: ns})
+			}
+		}
+		to, _, _ := unstructuredv1.NestedSlice(n.UnstructuredContent(), "spec", "to")
+		for _, t := range to {
+			if tm, ok := t.(map[string]interface{}); ok {
+				group, _, _ := unstructuredv1.NestedString(tm, "group")
+				kind, _, _ := unstructuredv1.NestedString(tm, "kind")
+				name, _, _ := unstructuredv1.NestedString(tm, "name")
+				grant.To = append(grant.To, ObjectReference{Group: group, Kind: kind, Name: name})
+			}
+		}
+		result = append(result, grant)
+	}
+	return result
+}
+
+// gatewayAllowsRoutesFrom returns true if any listener of the provided Gateway
+// allows routes from the provided namespace to attach to it, based on the
+// listener's "allowedRoutes.namespaces" field.
+func gatewayAllowsRoutesFrom(gw *Node, ns string, getNamespaceLabelsFn func(string) labels.Set) bool {
+	listeners, _, _ := unstructuredv1.NestedSlice(gw.UnstructuredContent(), "spec", "listeners")
+	for _, l := range listeners {
+		lm, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		from, _, _ := unstructuredv1.NestedString(lm, "allowedRoutes", "namespaces", "from")
+		switch from {
+		case "All":
+			return true
+		case "Selector":
+			m, found, _ := unstructuredv1.NestedMap(lm, "allowedRoutes", "namespaces", "selector")
+			if !found {
+				continue
+			}
+			var ls metav1.LabelSelector
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &ls); err != nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(&ls)
+			if err != nil {
+				continue
+			}
+			if selector.Matches(getNamespaceLabelsFn(ns)) {
+				return true
+			}
+		default:
+			if ns == gw.Namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pruneDisallowedRouteReferences removes the relationships between Gateway API
+// routes & objects in other namespaces that the Gateway API doesn't allow them
+// to reference, ie. backends that aren't permitted by any ReferenceGrant in the
+// backend's namespace & parent Gateways whose listeners don't allow routes from
+// the route's namespace.
+func pruneDisallowedRouteReferences(nodeMap map[types.UID]*Node) {
+	getNamespaceLabelsFn := newNamespaceLabelsFn(nodeMap)
+	grants := getReferenceGrants(nodeMap)
+	isGrantedFn := func(from, to *Node) bool {
+		for ix := range grants {
+			if grants[ix].allows(from, to) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, route := range nodeMap {
+		if !isRouteKind(route.Group, route.Kind) {
+			continue
+		}
+		for uid, rset := range route.Dependencies {
+			dep, ok := nodeMap[uid]
+			if !ok {
+				continue
+			}
+			if _, ok := rset[RelationshipRouteCrossNamespaceBackend]; ok && !isGrantedFn(route, dep) {
+				route.RemoveDependency(uid, RelationshipRouteCrossNamespaceBackend)
+				dep.RemoveDependent(route.UID, RelationshipRouteCrossNamespaceBackend)
+			}
+			if dep.Missing || dep.Group != GatewayAPIGroupName || dep.Kind != "Gateway" || dep.Namespace == route.Namespace {
+				continue
+			}
+			if _, ok := rset[RelationshipRouteParent]; ok && !gatewayAllowsRoutesFrom(dep, route.Namespace, getNamespaceLabelsFn) {
+				route.RemoveDependency(uid, RelationshipRouteParent)
+				dep.RemoveDependent(route.UID, RelationshipRouteParent)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetRouteRelationships(t *testing.T) {
+	t.Parallel()
+
+	objects := newTestObjects(t, `
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: Gateway
+metadata:
+  name: gw
+  namespace: default
+spec:
+  gatewayClassName: example
+  listeners:
+  - name: http
+    port: 80
+    protocol: HTTP
+`, `
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: HTTPRoute
+metadata:
+  name: web
+  namespace: default
+spec:
+  parentRefs:
+  - name: gw
+  rules:
+  - backendRefs:
+    - name: web
+      port: 80
+    - name: api
+      namespace: other
+      port: 80
+    - name: db
+      namespace: other
+      port: 5432
+`, `
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: HTTPRoute
+metadata:
+  name: foreign
+  namespace: other
+spec:
+  parentRefs:
+  - name: gw
+    namespace: default
+`, `
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: ReferenceGrant
+metadata:
+  name: allow-web
+  namespace: other
+spec:
+  from:
+  - group: gateway.networking.k8s.io
+    kind: HTTPRoute
+    namespace: default
+  to:
+  - group: ""
+    kind: Service
+    name: api
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: default
+spec:
+  selector:
+    app: web
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-1
+  namespace: default
+  labels:
+    app: web
+spec:
+  containers:
+  - name: app
+    image: nginx
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: other
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: other
+`)
+	nodeMap := resolveTestGraph(t, objects, ResolveOptions{})
+
+	// Cross-namespace references that aren't allowed by a ReferenceGrant or by
+	// the Gateway's listeners are dropped
+	var edges []string
+	for _, e := range getTestEdges(nodeMap) {
+		if strings.Contains(e, "-[Route") {
+			edges = append(edges, e)
+		}
+	}
+	expected := []string{
+		"Gateway/default/gw -[RouteParent]-> HTTPRoute/default/web",
+		"Service/default/web -[RouteBackend]-> HTTPRoute/default/web",
+		"Service/other/api -[RouteCrossNamespaceBackend]-> HTTPRoute/default/web",
+	}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Fatalf("expected %q got %q", expected, edges)
+	}
+
+	// Pods must be reachable from the Gateway through its routes & their
+	// backends
+	var paths []string
+	for _, p := range FindShortestPaths(nodeMap, testUID("Gateway", "default", "gw"), testUID("Pod", "default", "web-1"), 0) {
+		paths = append(paths, testPathString(p))
+	}
+	expected = []string{
+		"Gateway/default/gw -[RouteParent]-> HTTPRoute/default/web <-[RouteBackend]- Service/default/web <-[Service]- Pod/default/web-1",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected %q got %q", expected, paths)
+	}
+
+	// Backends & their Pods must be dependencies of the route, the same way as
+	// the Services of an Ingress
+	depMap, err := ResolveDependencies(newTestRESTMapper(objects), objects, []types.UID{testUID("HTTPRoute", "default", "web")}, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("failed to resolve dependencies: %v", err)
+	}
+	var deps []string
+	for uid, n := range depMap {
+		if uid == n.UID {
+			deps = append(deps, testNodeName(n))
+		}
+	}
+	sort.Strings(deps)
+	expected = []string{
+		"Gateway/default/gw",
+		"HTTPRoute/default/web",
+		"Pod/default/web-1",
+		"ReferenceGrant/other/allow-web",
+		"Service/default/web",
+		"Service/other/api",
+	}
+	if !reflect.DeepEqual(deps, expected) {
+		t.Fatalf("expected %q got %q", expected, deps)
+	}
+}
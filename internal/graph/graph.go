@@ -3,26 +3,20 @@ package graph
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
-	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
-	eventsv1 "k8s.io/api/events/v1"
-	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
-	networkingv1 "k8s.io/api/networking/v1"
-	nodev1 "k8s.io/api/node/v1"
-	policyv1 "k8s.io/api/policy/v1"
-	policyv1beta1 "k8s.io/api/policy/v1beta1"
-	rbacv1 "k8s.io/api/rbac/v1"
-	storagev1 "k8s.io/api/storage/v1"
-	storagev1beta1 "k8s.io/api/storage/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
-	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 )
 
 // ObjectLabelSelectorKey is a compact representation of an ObjectLabelSelector.
@@ -30,36 +24,123 @@ import (
 type ObjectLabelSelectorKey string
 
 // ObjectLabelSelector is a reference to a collection of Kubernetes objects.
+//
+// If NamespaceSelector is set, objects in any namespace whose labels match the
+// NamespaceSelector are selected instead of only objects in Namespace. If
+// FieldSelector is set, only objects whose "metadata.name" &
+// "metadata.namespace" fields match the FieldSelector are selected. If
+// Tolerations is set, only objects with "NoSchedule" or "NoExecute" taints
+// (ie. Nodes) that are all tolerated by the Tolerations are selected.
 type ObjectLabelSelector struct {
-	Group     string
-	Kind      string
-	Namespace string
-	Selector  labels.Selector
+	Group             string
+	Kind              string
+	Namespace         string
+	NamespaceSelector labels.Selector
+	FieldSelector     fields.Selector
+	Selector          labels.Selector
+	Tolerations       []corev1.Toleration
 }
 
 // Key converts the ObjectLabelSelector into a ObjectLabelSelectorKey.
 func (o *ObjectLabelSelector) Key() ObjectLabelSelectorKey {
 	k := fmt.Sprintf("%s\\%s\\%s\\%s", o.Group, o.Kind, o.Namespace, o.Selector)
+	if o.NamespaceSelector != nil {
+		k = fmt.Sprintf("%s\\%s", k, o.NamespaceSelector)
+	}
+	if o.FieldSelector != nil {
+		k = fmt.Sprintf("%s\\%s", k, o.FieldSelector)
+	}
+	for _, t := range o.Tolerations {
+		k = fmt.Sprintf("%s\\%s", k, tolerationKey(t))
+	}
 	return ObjectLabelSelectorKey(k)
 }
 
+// tolerationKey converts the provided toleration into a compact string, with
+// its toleration seconds dereferenced.
+func tolerationKey(t corev1.Toleration) string {
+	seconds := ""
+	if t.TolerationSeconds != nil {
+		seconds = strconv.FormatInt(*t.TolerationSeconds, 10)
+	}
+	return fmt.Sprintf("%s:%s:%s:%s:%s", t.Key, t.Operator, t.Value, t.Effect, seconds)
+}
+
+// ObjectRuleSelectorKey is a compact representation of an ObjectRuleSelector.
+// Typically used as key types for maps.
+type ObjectRuleSelectorKey string
+
+// ObjectRuleSelector is a reference to a collection of Kubernetes objects,
+// matched the same way admission webhook rules & RBAC policy rules match
+// objects (ie. by API group, resource, name, scope, namespace labels & object
+// labels). Fields that are left empty match all objects.
+//
+// API versions aren't matched since objects are only listed in their preferred
+// version.
+type ObjectRuleSelector struct {
+	Groups            []string
+	ExcludeGroups     []string
+	Resources         []string
+	Names             []string
+	Namespace         string
+	Scope             string
+	NamespaceSelector labels.Selector
+	Selector          labels.Selector
+}
+
+// Key converts the ObjectRuleSelector into a ObjectRuleSelectorKey.
+func (o *ObjectRuleSelector) Key() ObjectRuleSelectorKey {
+	k := fmt.Sprintf("%s\\%s\\%s\\%s\\%s\\%s\\%s\\%s",
+		strings.Join(o.Groups, ","), strings.Join(o.ExcludeGroups, ","),
+		strings.Join(o.Resources, ","), strings.Join(o.Names, ","),
+		o.Namespace, o.Scope, o.NamespaceSelector, o.Selector)
+	return ObjectRuleSelectorKey(k)
+}
+
 // ObjectSelectorKey is a compact representation of an ObjectSelector.
 // Typically used as key types for maps.
 type ObjectSelectorKey string
 
 // ObjectSelector is a reference to a collection of Kubernetes objects.
+// If OrdinalNamePrefix is set, only objects whose names consist of the prefix
+// followed by an ordinal (eg. "data-web-0") are selected.
 type ObjectSelector struct {
-	Group      string
-	Kind       string
-	Namespaces sets.String
+	Group             string
+	Kind              string
+	Namespaces        sets.String
+	OrdinalNamePrefix string
 }
 
 // Key converts the ObjectSelector into a ObjectSelectorKey.
 func (o *ObjectSelector) Key() ObjectSelectorKey {
 	k := fmt.Sprintf("%s\\%s\\%s", o.Group, o.Kind, o.Namespaces)
+	if len(o.OrdinalNamePrefix) > 0 {
+		k = fmt.Sprintf("%s\\%s", k, o.OrdinalNamePrefix)
+	}
 	return ObjectSelectorKey(k)
 }
 
+// matchesName returns true if the provided object name is selected by the
+// ObjectSelector's OrdinalNamePrefix.
+func (o *ObjectSelector) matchesName(name string) bool {
+	if len(o.OrdinalNamePrefix) == 0 {
+		return true
+	}
+	if !strings.HasPrefix(name, o.OrdinalNamePrefix) {
+		return false
+	}
+	ordinal := strings.TrimPrefix(name, o.OrdinalNamePrefix)
+	if len(ordinal) == 0 {
+		return false
+	}
+	for _, c := range ordinal {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // ObjectReferenceKey is a compact representation of an ObjectReference.
 // Typically used as key types for maps.
 type ObjectReferenceKey string
@@ -78,6 +159,20 @@ func (o *ObjectReference) Key() ObjectReferenceKey {
 	return ObjectReferenceKey(k)
 }
 
+// ObjectReference converts the ObjectReferenceKey back into a ObjectReference.
+func (k ObjectReferenceKey) ObjectReference() ObjectReference {
+	tokens := strings.SplitN(string(k), "\\", 4)
+	for len(tokens) < 4 {
+		tokens = append(tokens, "")
+	}
+	return ObjectReference{
+		Group:     tokens[0],
+		Kind:      tokens[1],
+		Namespace: tokens[2],
+		Name:      tokens[3],
+	}
+}
+
 type sortableStringSlice []string
 
 func (s sortableStringSlice) Len() int           { return len(s) }
@@ -101,31 +196,42 @@ func (s RelationshipSet) List() []string {
 }
 
 // RelationshipMap contains a map of relationships a Kubernetes object has with
-// other objects in the cluster.
+// other objects in the cluster. References in OptionalDependenciesByRef are to
+// objects that are allowed to not exist (eg. ConfigMaps & Secrets referenced
+// with "optional: true"), which are never resolved to missing nodes.
 type RelationshipMap struct {
 	DependenciesByLabelSelector map[ObjectLabelSelectorKey]RelationshipSet
 	DependenciesByRef           map[ObjectReferenceKey]RelationshipSet
+	DependenciesByRuleSelector  map[ObjectRuleSelectorKey]RelationshipSet
 	DependenciesBySelector      map[ObjectSelectorKey]RelationshipSet
 	DependenciesByUID           map[types.UID]RelationshipSet
 	DependentsByLabelSelector   map[ObjectLabelSelectorKey]RelationshipSet
 	DependentsByRef             map[ObjectReferenceKey]RelationshipSet
+	DependentsByRuleSelector    map[ObjectRuleSelectorKey]RelationshipSet
 	DependentsBySelector        map[ObjectSelectorKey]RelationshipSet
 	DependentsByUID             map[types.UID]RelationshipSet
+	OptionalDependenciesByRef   map[ObjectReferenceKey]RelationshipSet
 	ObjectLabelSelectors        map[ObjectLabelSelectorKey]ObjectLabelSelector
+	ObjectRuleSelectors         map[ObjectRuleSelectorKey]ObjectRuleSelector
 	ObjectSelectors             map[ObjectSelectorKey]ObjectSelector
 }
 
-func newRelationshipMap() RelationshipMap {
+// NewRelationshipMap returns an empty RelationshipMap.
+func NewRelationshipMap() RelationshipMap {
 	return RelationshipMap{
 		DependenciesByLabelSelector: map[ObjectLabelSelectorKey]RelationshipSet{},
 		DependenciesByRef:           map[ObjectReferenceKey]RelationshipSet{},
+		DependenciesByRuleSelector:  map[ObjectRuleSelectorKey]RelationshipSet{},
 		DependenciesBySelector:      map[ObjectSelectorKey]RelationshipSet{},
 		DependenciesByUID:           map[types.UID]RelationshipSet{},
 		DependentsByLabelSelector:   map[ObjectLabelSelectorKey]RelationshipSet{},
 		DependentsByRef:             map[ObjectReferenceKey]RelationshipSet{},
+		DependentsByRuleSelector:    map[ObjectRuleSelectorKey]RelationshipSet{},
 		DependentsBySelector:        map[ObjectSelectorKey]RelationshipSet{},
 		DependentsByUID:             map[types.UID]RelationshipSet{},
+		OptionalDependenciesByRef:   map[ObjectReferenceKey]RelationshipSet{},
 		ObjectLabelSelectors:        map[ObjectLabelSelectorKey]ObjectLabelSelector{},
+		ObjectRuleSelectors:         map[ObjectRuleSelectorKey]ObjectRuleSelector{},
 		ObjectSelectors:             map[ObjectSelectorKey]ObjectSelector{},
 	}
 }
@@ -146,6 +252,15 @@ func (m *RelationshipMap) AddDependencyByLabelSelector(o ObjectLabelSelector, r
 	m.ObjectLabelSelectors[k] = o
 }
 
+func (m *RelationshipMap) AddDependencyByRuleSelector(o ObjectRuleSelector, r Relationship) {
+	k := o.Key()
+	if _, ok := m.DependenciesByRuleSelector[k]; !ok {
+		m.DependenciesByRuleSelector[k] = RelationshipSet{}
+	}
+	m.DependenciesByRuleSelector[k][r] = struct{}{}
+	m.ObjectRuleSelectors[k] = o
+}
+
 func (m *RelationshipMap) AddDependencyBySelector(o ObjectSelector, r Relationship) {
 	k := o.Key()
 	if _, ok := m.DependenciesBySelector[k]; !ok {
@@ -178,6 +293,15 @@ func (m *RelationshipMap) AddDependentByLabelSelector(o ObjectLabelSelector, r R
 	m.ObjectLabelSelectors[k] = o
 }
 
+func (m *RelationshipMap) AddDependentByRuleSelector(o ObjectRuleSelector, r Relationship) {
+	k := o.Key()
+	if _, ok := m.DependentsByRuleSelector[k]; !ok {
+		m.DependentsByRuleSelector[k] = RelationshipSet{}
+	}
+	m.DependentsByRuleSelector[k][r] = struct{}{}
+	m.ObjectRuleSelectors[k] = o
+}
+
 func (m *RelationshipMap) AddDependentBySelector(o ObjectSelector, r Relationship) {
 	k := o.Key()
 	if _, ok := m.DependentsBySelector[k]; !ok {
@@ -194,6 +318,13 @@ func (m *RelationshipMap) AddDependentByUID(uid types.UID, r Relationship) {
 	m.DependentsByUID[uid][r] = struct{}{}
 }
 
+func (m *RelationshipMap) AddOptionalDependencyByKey(k ObjectReferenceKey, r Relationship) {
+	if _, ok := m.OptionalDependenciesByRef[k]; !ok {
+		m.OptionalDependenciesByRef[k] = RelationshipSet{}
+	}
+	m.OptionalDependenciesByRef[k][r] = struct{}{}
+}
+
 // Node represents a Kubernetes object in an relationship tree.
 type Node struct {
 	*unstructuredv1.Unstructured
@@ -209,6 +340,16 @@ type Node struct {
 	Dependencies    map[types.UID]RelationshipSet
 	Dependents      map[types.UID]RelationshipSet
 	Depth           uint
+	// Missing is true if the object is referenced by other objects but doesn't
+	// exist in the list of provided objects.
+	Missing bool
+	// Synthetic is true if the node doesn't represent a Kubernetes object (eg.
+	// users & groups that are subjects of role bindings).
+	Synthetic bool
+	// AggregatedRules contains the effective policy rules of an aggregated
+	// ClusterRole, computed from the ClusterRoles selected by its aggregation
+	// rule instead of the "rules" field rewritten by the aggregation controller.
+	AggregatedRules []AggregatedPolicyRule
 }
 
 func (n *Node) AddDependency(uid types.UID, r Relationship) {
@@ -225,6 +366,24 @@ func (n *Node) AddDependent(uid types.UID, r Relationship) {
 	n.Dependents[uid][r] = struct{}{}
 }
 
+func (n *Node) RemoveDependency(uid types.UID, r Relationship) {
+	if rset, ok := n.Dependencies[uid]; ok {
+		delete(rset, r)
+		if len(rset) == 0 {
+			delete(n.Dependencies, uid)
+		}
+	}
+}
+
+func (n *Node) RemoveDependent(uid types.UID, r Relationship) {
+	if rset, ok := n.Dependents[uid]; ok {
+		delete(rset, r)
+		if len(rset) == 0 {
+			delete(n.Dependents, uid)
+		}
+	}
+}
+
 func (n *Node) GetDeps(depsIsDependencies bool) map[types.UID]RelationshipSet {
 	if depsIsDependencies {
 		return n.Dependencies
@@ -293,23 +452,123 @@ type NodeMap map[types.UID]*Node
 
 // ResolveDependencies resolves all dependencies of the provided objects and
 // returns a relationship tree.
-func ResolveDependencies(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids []types.UID) (NodeMap, error) {
-	return resolveDeps(m, objects, uids, true)
+func ResolveDependencies(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids []types.UID, opts ResolveOptions) (NodeMap, error) {
+	return resolveDeps(m, objects, uids, opts, true)
 }
 
 // ResolveDependents resolves all dependents of the provided objects and returns
 // a relationship tree.
-func ResolveDependents(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids []types.UID) (NodeMap, error) {
-	return resolveDeps(m, objects, uids, false)
+func ResolveDependents(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids []types.UID, opts ResolveOptions) (NodeMap, error) {
+	return resolveDeps(m, objects, uids, opts, false)
+}
+
+// ResolveOptions contains the options for resolving relationships between
+// objects.
+type ResolveOptions struct {
+	// IncludeMissing includes nodes for objects that are referenced by other
+	// objects but don't exist in the list of provided objects.
+	IncludeMissing bool
+	// IncludeSubjects includes synthetic nodes for the users & groups that are
+	// subjects of RoleBindings & ClusterRoleBindings.
+	IncludeSubjects bool
+	// PolicyRuleVerbs includes relationships between RoleBindings &
+	// ClusterRoleBindings and the objects that the policy rules of their roles
+	// allow any of the provided verbs to be performed on. No such relationships
+	// are included if it's empty.
+	PolicyRuleVerbs []string
+	// IncludeWildcardPolicyRules includes relationships of policy rules that
+	// match all API groups or resources (ie. "*"), which would otherwise relate
+	// roles like "cluster-admin" to nearly every object.
+	IncludeWildcardPolicyRules bool
+}
+
+// ResolveGraph resolves the relationships between all of the provided objects
+// and returns the relationship graph of all objects.
+func ResolveGraph(m meta.RESTMapper, objects []unstructuredv1.Unstructured, opts ResolveOptions) (NodeMap, error) {
+	globalMapByUID, err := resolveGlobalMap(m, objects, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Exclude the additional keys used for looking up nodes (e.g. Nodes are also
+	// mapped by their name)
+	nodeMap := NodeMap{}
+	for uid, node := range globalMapByUID {
+		if uid == node.UID {
+			nodeMap[uid] = node
+		}
+	}
+	return nodeMap, nil
 }
 
 // resolveDeps resolves all dependencies or dependents of the provided objects
 // and returns a relationship tree.
-//nolint:funlen,gocognit,gocyclo
-func resolveDeps(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids []types.UID, depsIsDependencies bool) (NodeMap, error) {
+func resolveDeps(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids []types.UID, opts ResolveOptions, depsIsDependencies bool) (NodeMap, error) {
 	if len(uids) == 0 {
 		return NodeMap{}, nil
 	}
+	globalMapByUID, err := resolveGlobalMap(m, objects, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create submap containing the provided objects & either their dependencies
+	// or dependents from the global map
+	var depth uint
+	nodeMap, uidQueue, uidSet := NodeMap{}, []types.UID{}, map[types.UID]struct{}{}
+	for _, uid := range uids {
+		if node := globalMapByUID[uid]; node != nil {
+			nodeMap[uid] = node
+			uidQueue = append(uidQueue, uid)
+		}
+	}
+	depth, uidQueue = 0, append(uidQueue, "")
+	for {
+		if len(uidQueue) <= 1 {
+			break
+		}
+		uid := uidQueue[0]
+		if uid == "" {
+			depth, uidQueue = depth+1, append(uidQueue[1:], "")
+			continue
+		}
+
+		// Guard against possible cycles
+		if _, ok := uidSet[uid]; ok {
+			uidQueue = uidQueue[1:]
+			continue
+		} else {
+			uidSet[uid] = struct{}{}
+		}
+
+		if node := nodeMap[uid]; node != nil {
+			// Allow nodes to keep the smallest depth. For example, if a node has a
+			// depth of 1 & 7 in the relationship tree, we keep 1 so that when
+			// printing the tree with a depth of 2, the node will still be printed
+			if node.Depth == 0 || depth < node.Depth {
+				node.Depth = depth
+			}
+			deps := node.GetDeps(depsIsDependencies)
+			depUIDs, ix := make([]types.UID, len(deps)), 0
+			for depUID := range deps {
+				nodeMap[depUID] = globalMapByUID[depUID]
+				depUIDs[ix] = depUID
+				ix++
+			}
+			uidQueue = append(uidQueue[1:], depUIDs...)
+		}
+	}
+
+	klog.V(4).Infof("Resolved %d deps for %d objects", len(nodeMap)-1, len(uids))
+	return nodeMap, nil
+}
+
+// resolveGlobalMap resolves the relationships between all of the provided
+// objects and returns a map of all nodes mapped by their UIDs. Objects that are
+// referenced but don't exist & subjects of role bindings are included as
+// missing & synthetic nodes respectively if the options say so.
+//
+//nolint:funlen,gocognit,gocyclo
+func resolveGlobalMap(m meta.RESTMapper, objects []unstructuredv1.Unstructured, opts ResolveOptions) (map[types.UID]*Node, error) {
 	// Create global node maps of all objects, one mapped by node UIDs & the other
 	// mapped by node keys. This step also helps deduplicate the list of provided
 	// objects
@@ -358,21 +617,97 @@ func resolveDeps(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids
 		}
 	}
 
+	// Namespace labels are used to resolve label selectors with a namespace
+	// selector
+	getNamespaceLabelsFn := newNamespaceLabelsFn(globalMapByUID)
+	matchNamespaceFn := func(o ObjectLabelSelector, ns string) bool {
+		if o.NamespaceSelector == nil {
+			return ns == o.Namespace
+		}
+		if len(ns) == 0 {
+			return false
+		}
+		return o.NamespaceSelector.Matches(getNamespaceLabelsFn(ns))
+	}
+	matchFieldsFn := func(o ObjectLabelSelector, n *Node) bool {
+		if o.FieldSelector == nil {
+			return true
+		}
+		return o.FieldSelector.Matches(fields.Set{"metadata.name": n.Name, "metadata.namespace": n.Namespace})
+	}
+	matchTolerationsFn := func(o ObjectLabelSelector, n *Node) bool {
+		if len(o.Tolerations) == 0 {
+			return true
+		}
+		return toleratesTaints(o.Tolerations, getTaints(n))
+	}
 	resolveLabelSelectorToNodes := func(o ObjectLabelSelector) []*Node {
 		var result []*Node
 		for _, n := range globalMapByUID {
-			if n.Group == o.Group && n.Kind == o.Kind && n.Namespace == o.Namespace {
-				if ok := o.Selector.Matches(labels.Set(n.GetLabels())); ok {
+			if n.Group == o.Group && n.Kind == o.Kind && matchNamespaceFn(o, n.Namespace) {
+				if ok := o.Selector.Matches(labels.Set(n.GetLabels())); !ok {
+					continue
+				}
+				if matchFieldsFn(o, n) && matchTolerationsFn(o, n) {
 					result = append(result, n)
 				}
 			}
 		}
 		return result
 	}
+	resolveRuleSelectorToNodes := func(o ObjectRuleSelector) []*Node {
+		matchFn := func(values []string, v string) bool {
+			for _, val := range values {
+				if val == "*" || val == "*/*" || val == v {
+					return true
+				}
+			}
+			return false
+		}
+		var result []*Node
+		for _, n := range globalMapByUID {
+			if !matchFn(o.Groups, n.Group) || !matchFn(o.Resources, n.Resource) {
+				continue
+			}
+			if len(o.ExcludeGroups) > 0 && sets.NewString(o.ExcludeGroups...).Has(n.Group) {
+				continue
+			}
+			if len(o.Names) > 0 && !sets.NewString(o.Names...).Has(n.Name) {
+				continue
+			}
+			if len(o.Namespace) > 0 && n.Namespace != o.Namespace {
+				continue
+			}
+			switch {
+			case o.Scope == "Cluster" && n.Namespaced:
+				continue
+			case o.Scope == "Namespaced" && !n.Namespaced:
+				continue
+			}
+			// The namespace selector of cluster-scoped objects is matched against
+			// the object itself if it's a namespace, otherwise it always matches
+			if nsSelector := o.NamespaceSelector; nsSelector != nil {
+				switch {
+				case n.Namespaced:
+					if !nsSelector.Matches(getNamespaceLabelsFn(n.Namespace)) {
+						continue
+					}
+				case n.Group == corev1.GroupName && n.Kind == "Namespace":
+					if !nsSelector.Matches(getNamespaceLabelsFn(n.Name)) {
+						continue
+					}
+				}
+			}
+			if o.Selector == nil || o.Selector.Matches(labels.Set(n.GetLabels())) {
+				result = append(result, n)
+			}
+		}
+		return result
+	}
 	resolveSelectorToNodes := func(o ObjectSelector) []*Node {
 		var result []*Node
 		for _, n := range globalMapByUID {
-			if n.Group == o.Group && n.Kind == o.Kind {
+			if n.Group == o.Group && n.Kind == o.Kind && o.matchesName(n.Name) {
 				if len(o.Namespaces) == 0 || o.Namespaces.Has(n.Namespace) {
 					result = append(result, n)
 				}
@@ -380,8 +715,43 @@ func resolveDeps(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids
 		}
 		return result
 	}
+	// Objects that are referenced but don't exist & synthetic nodes are tracked
+	// separately, so that they're not processed while iterating through the
+	// global map
+	missingMapByKey := map[ObjectReferenceKey]*Node{}
+	resolveRefToNode := func(k ObjectReferenceKey) (*Node, bool) {
+		if n, ok := globalMapByKey[k]; ok {
+			return n, true
+		}
+		ref := k.ObjectReference()
+		if n, ok := missingMapByKey[k]; ok {
+			return n, true
+		}
+		var n *Node
+		switch {
+		case isSubjectReference(ref):
+			if !opts.IncludeSubjects {
+				return nil, false
+			}
+			n = newSubjectNode(ref)
+		case opts.IncludeMissing && len(ref.Kind) != 0 && len(ref.Name) != 0:
+			n = newMissingNode(ref)
+		default:
+			return nil, false
+		}
+		missingMapByKey[k] = n
+		return n, true
+	}
 	updateRelationships := func(node *Node, rmap *RelationshipMap) {
 		for k, rset := range rmap.DependenciesByRef {
+			if n, ok := resolveRefToNode(k); ok {
+				for r := range rset {
+					node.AddDependency(n.UID, r)
+					n.AddDependent(node.UID, r)
+				}
+			}
+		}
+		for k, rset := range rmap.OptionalDependenciesByRef {
 			if n, ok := globalMapByKey[k]; ok {
 				for r := range rset {
 					node.AddDependency(n.UID, r)
@@ -390,7 +760,7 @@ func resolveDeps(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids
 			}
 		}
 		for k, rset := range rmap.DependentsByRef {
-			if n, ok := globalMapByKey[k]; ok {
+			if n, ok := resolveRefToNode(k); ok {
 				for r := range rset {
 					n.AddDependency(node.UID, r)
 					node.AddDependent(n.UID, r)
@@ -417,6 +787,26 @@ func resolveDeps(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids
 				}
 			}
 		}
+		for k, rset := range rmap.DependenciesByRuleSelector {
+			if os, ok := rmap.ObjectRuleSelectors[k]; ok {
+				for _, n := range resolveRuleSelectorToNodes(os) {
+					for r := range rset {
+						node.AddDependency(n.UID, r)
+						n.AddDependent(node.UID, r)
+					}
+				}
+			}
+		}
+		for k, rset := range rmap.DependentsByRuleSelector {
+			if os, ok := rmap.ObjectRuleSelectors[k]; ok {
+				for _, n := range resolveRuleSelectorToNodes(os) {
+					for r := range rset {
+						n.AddDependency(node.UID, r)
+						node.AddDependent(n.UID, r)
+					}
+				}
+			}
+		}
 		for k, rset := range rmap.DependenciesBySelector {
 			if os, ok := rmap.ObjectSelectors[k]; ok {
 				for _, n := range resolveSelectorToNodes(os) {
@@ -469,224 +859,153 @@ func resolveDeps(m meta.RESTMapper, objects []unstructuredv1.Unstructured, uids
 		}
 	}
 
-	var rmap *RelationshipMap
-	var err error
+	// Compute the effective policy rules of aggregated ClusterRoles before
+	// resolving relationships, so that relationships based on policy rules use
+	// them instead of the rules the aggregation controller may not have written
+	resolveAggregatedRules(globalMapByUID)
+
+	// Populate dependencies & dependents based on the relationships returned
+	// by the resolver registered for each object's resource type
 	for _, node := range globalMapByUID {
-		switch {
-		// Populate dependencies & dependents based on PersistentVolume relationships
-		case node.Group == corev1.GroupName && node.Kind == "PersistentVolume":
-			rmap, err = getPersistentVolumeRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for persistentvolume named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on PersistentVolumeClaim relationships
-		case node.Group == corev1.GroupName && node.Kind == "PersistentVolumeClaim":
-			rmap, err = getPersistentVolumeClaimRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for persistentvolumeclaim named \"%s\" in namespace \"%s\": %s", node.Name, node.Namespace, err)
-				continue
-			}
-		// Populate dependencies & dependents based on Pod relationships
-		case node.Group == corev1.GroupName && node.Kind == "Pod":
-			rmap, err = getPodRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for pod named \"%s\" in namespace \"%s\": %s", node.Name, node.Namespace, err)
-				continue
-			}
-		// Populate dependencies & dependents based on Service relationships
-		case node.Group == corev1.GroupName && node.Kind == "Service":
-			rmap, err = getServiceRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for service named \"%s\" in namespace \"%s\": %s", node.Name, node.Namespace, err)
-				continue
-			}
-		// Populate dependencies & dependents based on ServiceAccount relationships
-		case node.Group == corev1.GroupName && node.Kind == "ServiceAccount":
-			rmap, err = getServiceAccountRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for serviceaccount named \"%s\" in namespace \"%s\": %s", node.Name, node.Namespace, err)
-				continue
-			}
-		// Populate dependencies & dependents based on PodSecurityPolicy relationships
-		case node.Group == policyv1beta1.GroupName && node.Kind == "PodSecurityPolicy":
-			rmap, err = getPodSecurityPolicyRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for podsecuritypolicy named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on PodDisruptionBudget relationships
-		case node.Group == policyv1.GroupName && node.Kind == "PodDisruptionBudget":
-			rmap, err = getPodDisruptionBudgetRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for poddisruptionbudget named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on MutatingWebhookConfiguration relationships
-		case node.Group == admissionregistrationv1.GroupName && node.Kind == "MutatingWebhookConfiguration":
-			rmap, err = getMutatingWebhookConfigurationRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for mutatingwebhookconfiguration named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on ValidatingWebhookConfiguration relationships
-		case node.Group == admissionregistrationv1.GroupName && node.Kind == "ValidatingWebhookConfiguration":
-			rmap, err = getValidatingWebhookConfigurationRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for validatingwebhookconfiguration named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on APIService relationships
-		case node.Group == apiregistrationv1.GroupName && node.Kind == "APIService":
-			rmap, err = getAPIServiceRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for apiservice named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on Event relationships
-		case (node.Group == eventsv1.GroupName || node.Group == corev1.GroupName) && node.Kind == "Event":
-			rmap, err = getEventRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for event named \"%s\" in namespace \"%s\": %s", node.Name, node.Namespace, err)
-				continue
-			}
-		// Populate dependencies & dependents based on Ingress relationships
-		case (node.Group == networkingv1.GroupName || node.Group == extensionsv1beta1.GroupName) && node.Kind == "Ingress":
-			rmap, err = getIngressRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for ingress named \"%s\" in namespace \"%s\": %s", node.Name, node.Namespace, err)
-				continue
-			}
-		// Populate dependencies & dependents based on IngressClass relationships
-		case node.Group == networkingv1.GroupName && node.Kind == "IngressClass":
-			rmap, err = getIngressClassRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for ingressclass named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on NetworkPolicy relationships
-		case node.Group == networkingv1.GroupName && node.Kind == "NetworkPolicy":
-			rmap, err = getNetworkPolicyRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for networkpolicy named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on RuntimeClass relationships
-		case node.Group == nodev1.GroupName && node.Kind == "RuntimeClass":
-			rmap, err = getRuntimeClassRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for runtimeclass named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on ClusterRole relationships
-		case node.Group == rbacv1.GroupName && node.Kind == "ClusterRole":
-			rmap, err = getClusterRoleRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for clusterrole named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on ClusterRoleBinding relationships
-		case node.Group == rbacv1.GroupName && node.Kind == "ClusterRoleBinding":
-			rmap, err = getClusterRoleBindingRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for clusterrolebinding named \"%s\": %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on Role relationships
-		case node.Group == rbacv1.GroupName && node.Kind == "Role":
-			rmap, err = getRoleRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for role named \"%s\" in namespace \"%s\": %s: %s", node.Name, node.Namespace, err)
-				continue
-			}
-		// Populate dependencies & dependents based on RoleBinding relationships
-		case node.Group == rbacv1.GroupName && node.Kind == "RoleBinding":
-			rmap, err = getRoleBindingRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for rolebinding named \"%s\" in namespace \"%s\": %s: %s", node.Name, node.Namespace, err)
-				continue
-			}
-		// Populate dependencies & dependents based on CSIStorageCapacity relationships
-		case node.Group == storagev1beta1.GroupName && node.Kind == "CSIStorageCapacity":
-			rmap, err = getCSIStorageCapacityRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for csistoragecapacity named \"%s\": %s: %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on CSINode relationships
-		case node.Group == storagev1.GroupName && node.Kind == "CSINode":
-			rmap, err = getCSINodeRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for csinode named \"%s\": %s: %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on StorageClass relationships
-		case node.Group == storagev1.GroupName && node.Kind == "StorageClass":
-			rmap, err = getStorageClassRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for storageclass named \"%s\": %s: %s", node.Name, err)
-				continue
-			}
-		// Populate dependencies & dependents based on VolumeAttachment relationships
-		case node.Group == storagev1.GroupName && node.Kind == "VolumeAttachment":
-			rmap, err = getVolumeAttachmentRelationships(node)
-			if err != nil {
-				klog.V(4).Infof("Failed to get relationships for volumeattachment named \"%s\": %s: %s", node.Name, err)
-				continue
+		resolve, ok := GetResolver(schema.GroupKind{Group: node.Group, Kind: node.Kind})
+		if !ok {
+			continue
+		}
+		rmap, err := resolve(node)
+		if err != nil {
+			if node.Namespaced {
+				klog.V(4).Infof("Failed to get relationships for %s named \"%s\" in namespace \"%s\": %s", strings.ToLower(node.Kind), node.Name, node.Namespace, err)
+			} else {
+				klog.V(4).Infof("Failed to get relationships for %s named \"%s\": %s", strings.ToLower(node.Kind), node.Name, err)
 			}
-		default:
 			continue
 		}
 		updateRelationships(node, rmap)
 	}
 
-	// Create submap containing the provided objects & either their dependencies
-	// or dependents from the global map
-	var depth uint
-	nodeMap, uidQueue, uidSet := NodeMap{}, []types.UID{}, map[types.UID]struct{}{}
-	for _, uid := range uids {
-		if node := globalMapByUID[uid]; node != nil {
-			nodeMap[uid] = node
-			uidQueue = append(uidQueue, uid)
+	// Populate dependencies & dependents based on the objects that bindings
+	// grant access to through the policy rules of their roles
+	if len(opts.PolicyRuleVerbs) > 0 {
+		resolvePolicyRuleRelationships(globalMapByUID, opts.PolicyRuleVerbs, opts.IncludeWildcardPolicyRules)
+	}
+
+	for k, n := range missingMapByKey {
+		globalMapByUID[n.UID] = n
+		globalMapByKey[k] = n
+	}
+
+	// Run the post-resolve hooks, which may drop relationships that depend on
+	// other objects, along with the missing objects that are no longer
+	// referenced by any object
+	for _, hook := range getPostResolveHooks() {
+		hook(globalMapByUID)
+	}
+	for k, n := range missingMapByKey {
+		if len(n.Dependencies) == 0 && len(n.Dependents) == 0 {
+			delete(globalMapByUID, n.UID)
+			delete(globalMapByKey, k)
 		}
 	}
-	depth, uidQueue = 0, append(uidQueue, "")
-	for {
-		if len(uidQueue) <= 1 {
-			break
+
+	return globalMapByUID, nil
+}
+
+// newNamespaceLabelsFn returns a function that returns the labels of a
+// namespace, based on the Namespaces in the provided node map. Namespaces that
+// weren't listed are only matched by their "kubernetes.io/metadata.name" label,
+// which is set on every namespace.
+func newNamespaceLabelsFn(nodeMap map[types.UID]*Node) func(string) labels.Set {
+	namespaceLabels := map[string]labels.Set{}
+	for _, n := range nodeMap {
+		if n.Group == corev1.GroupName && n.Kind == "Namespace" && !n.Missing {
+			namespaceLabels[n.Name] = labels.Set(n.GetLabels())
 		}
-		uid := uidQueue[0]
-		if uid == "" {
-			depth, uidQueue = depth+1, append(uidQueue[1:], "")
-			continue
+	}
+	return func(ns string) labels.Set {
+		if nsLabels, ok := namespaceLabels[ns]; ok {
+			return nsLabels
 		}
+		return labels.Set{corev1.LabelMetadataName: ns}
+	}
+}
 
-		// Guard against possible cycles
-		if _, ok := uidSet[uid]; ok {
-			uidQueue = uidQueue[1:]
+// getTaints returns the taints of the provided object (ie. a Node's
+// "spec.taints" field).
+func getTaints(n *Node) []corev1.Taint {
+	var result []corev1.Taint
+	taints, _, _ := unstructuredv1.NestedSlice(n.UnstructuredContent(), "spec", "taints")
+	for _, t := range taints {
+		tm, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var taint corev1.Taint
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(tm, &taint); err != nil {
 			continue
-		} else {
-			uidSet[uid] = struct{}{}
 		}
+		result = append(result, taint)
+	}
+	return result
+}
 
-		if node := nodeMap[uid]; node != nil {
-			// Allow nodes to keep the smallest depth. For example, if a node has a
-			// depth of 1 & 7 in the relationship tree, we keep 1 so that when
-			// printing the tree with a depth of 2, the node will still be printed
-			if node.Depth == 0 || depth < node.Depth {
-				node.Depth = depth
-			}
-			deps := node.GetDeps(depsIsDependencies)
-			depUIDs, ix := make([]types.UID, len(deps)), 0
-			for depUID := range deps {
-				nodeMap[depUID] = globalMapByUID[depUID]
-				depUIDs[ix] = depUID
-				ix++
+// toleratesTaints returns true if the provided taints include at least one
+// "NoSchedule" or "NoExecute" taint & all of them are tolerated by the provided
+// tolerations. "PreferNoSchedule" taints are ignored since they don't prevent
+// pods from being scheduled.
+func toleratesTaints(tolerations []corev1.Toleration, taints []corev1.Taint) bool {
+	found := false
+	for ix := range taints {
+		if taints[ix].Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		found = true
+		tolerated := false
+		for jx := range tolerations {
+			if tolerations[jx].ToleratesTaint(&taints[ix]) {
+				tolerated = true
+				break
 			}
-			uidQueue = append(uidQueue[1:], depUIDs...)
+		}
+		if !tolerated {
+			return false
 		}
 	}
+	return found
+}
 
-	klog.V(4).Infof("Resolved %d deps for %d objects", len(nodeMap)-1, len(uids))
-	return nodeMap, nil
+// newMissingNode returns a node representing an object that is referenced by
+// other objects but doesn't exist.
+func newMissingNode(ref ObjectReference) *Node {
+	n := newReferenceNode(ref, "missing:")
+	n.Missing = true
+	return n
+}
+
+// newSubjectNode returns a synthetic node representing a user or group that is
+// a subject of role bindings.
+func newSubjectNode(ref ObjectReference) *Node {
+	n := newReferenceNode(ref, "subject:")
+	n.Synthetic = true
+	return n
+}
+
+// newReferenceNode returns a node representing the provided object reference,
+// using the reference's key with the provided prefix as its UID.
+func newReferenceNode(ref ObjectReference, uidPrefix string) *Node {
+	u := &unstructuredv1.Unstructured{}
+	u.SetAPIVersion(schema.GroupVersion{Group: ref.Group}.String())
+	u.SetKind(ref.Kind)
+	u.SetNamespace(ref.Namespace)
+	u.SetName(ref.Name)
+	return &Node{
+		Unstructured: u,
+		UID:          types.UID(uidPrefix + string(ref.Key())),
+		Name:         ref.Name,
+		Namespace:    ref.Namespace,
+		Namespaced:   ref.Namespace != "",
+		Group:        ref.Group,
+		Kind:         ref.Kind,
+		Dependencies: map[types.UID]RelationshipSet{},
+		Dependents:   map[types.UID]RelationshipSet{},
+	}
 }
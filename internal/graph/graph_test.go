@@ -0,0 +1,345 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// newTestObjects returns the objects of the provided YAML manifests. Objects
+// without an UID are assigned one generated from their kind, namespace & name.
+func newTestObjects(t *testing.T, manifests ...string) []unstructuredv1.Unstructured {
+	t.Helper()
+
+	objects := make([]unstructuredv1.Unstructured, 0, len(manifests))
+	for _, m := range manifests {
+		data, err := yaml.YAMLToJSON([]byte(m))
+		if err != nil {
+			t.Fatalf("failed to convert manifest to JSON: %v", err)
+		}
+		var u unstructuredv1.Unstructured
+		if err := u.UnmarshalJSON(data); err != nil {
+			t.Fatalf("failed to decode manifest: %v", err)
+		}
+		if len(u.GetUID()) == 0 {
+			u.SetUID(testUID(u.GetKind(), u.GetNamespace(), u.GetName()))
+		}
+		objects = append(objects, u)
+	}
+	return objects
+}
+
+// testUID returns the UID assigned to test objects without an UID.
+func testUID(kind, ns, name string) types.UID {
+	if len(ns) == 0 {
+		return types.UID(kind + "/" + name)
+	}
+	return types.UID(kind + "/" + ns + "/" + name)
+}
+
+// newTestRESTMapper returns a RESTMapper that maps the resource types of the
+// provided objects.
+func newTestRESTMapper(objects []unstructuredv1.Unstructured) meta.RESTMapper {
+	m := meta.NewDefaultRESTMapper(nil)
+	for _, o := range objects {
+		scope := meta.RESTScopeNamespace
+		if len(o.GetNamespace()) == 0 {
+			scope = meta.RESTScopeRoot
+		}
+		m.Add(o.GroupVersionKind(), scope)
+	}
+	return m
+}
+
+// resolveTestGraph returns the relationship graph of the provided objects.
+func resolveTestGraph(t *testing.T, objects []unstructuredv1.Unstructured, opts ResolveOptions) NodeMap {
+	t.Helper()
+
+	nodeMap, err := ResolveGraph(newTestRESTMapper(objects), objects, opts)
+	if err != nil {
+		t.Fatalf("failed to resolve graph: %v", err)
+	}
+	return nodeMap
+}
+
+// testNodeName returns the name of the provided node in the
+// <kind>/[<namespace>/]<name> form, prefixed for missing & synthetic nodes.
+func testNodeName(n *Node) string {
+	name := string(testUID(n.Kind, n.Namespace, n.Name))
+	switch {
+	case n.Missing:
+		return "missing:" + name
+	case n.Synthetic:
+		return "subject:" + name
+	}
+	return name
+}
+
+// getTestEdges returns every relationship of the provided node map in the
+// "<object> -[<relationship>]-> <dependent>" form, sorted.
+func getTestEdges(nodeMap NodeMap) []string {
+	var edges []string
+	for _, n := range nodeMap {
+		for uid, rset := range n.Dependents {
+			dep, ok := nodeMap[uid]
+			if !ok {
+				continue
+			}
+			for r := range rset {
+				edges = append(edges, fmt.Sprintf("%s -[%s]-> %s", testNodeName(n), r, testNodeName(dep)))
+			}
+		}
+	}
+	sort.Strings(edges)
+	return edges
+}
+
+func TestResolveGraphMissingObjects(t *testing.T) {
+	t.Parallel()
+
+	podWithSecretVolume := func(optional bool) string {
+		return fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: foo
+  namespace: default
+spec:
+  containers:
+  - name: app
+    image: nginx
+  volumes:
+  - name: bar
+    secret:
+      secretName: bar
+      optional: %t
+`, optional)
+	}
+	secret := `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: bar
+  namespace: default
+`
+	endpoints := `
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: foo
+  namespace: default
+`
+
+	tests := []struct {
+		name      string
+		manifests []string
+		opts      ResolveOptions
+		expected  []string
+	}{
+		{
+			name:      "required reference to missing object",
+			manifests: []string{podWithSecretVolume(false)},
+			opts:      ResolveOptions{IncludeMissing: true},
+			expected:  []string{"missing:Secret/default/bar -[PodVolume]-> Pod/default/foo"},
+		},
+		{
+			name:      "required reference to missing object without missing objects included",
+			manifests: []string{podWithSecretVolume(false)},
+			opts:      ResolveOptions{},
+			expected:  nil,
+		},
+		{
+			name:      "optional reference to missing object",
+			manifests: []string{podWithSecretVolume(true)},
+			opts:      ResolveOptions{IncludeMissing: true},
+			expected:  nil,
+		},
+		{
+			name:      "optional reference to existing object",
+			manifests: []string{podWithSecretVolume(true), secret},
+			opts:      ResolveOptions{IncludeMissing: true},
+			expected:  []string{"Secret/default/bar -[PodVolume]-> Pod/default/foo"},
+		},
+		{
+			name:      "endpoints without service",
+			manifests: []string{endpoints},
+			opts:      ResolveOptions{IncludeMissing: true},
+			expected:  nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodeMap := resolveTestGraph(t, newTestObjects(t, tt.manifests...), tt.opts)
+			if edges := getTestEdges(nodeMap); !reflect.DeepEqual(edges, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, edges)
+			}
+			for _, n := range nodeMap {
+				if n.Missing && len(n.Dependencies) == 0 && len(n.Dependents) == 0 {
+					t.Fatalf("expected no unreferenced missing object got \"%s\"", testNodeName(n))
+				}
+			}
+		})
+	}
+}
+
+func TestToleratesTaints(t *testing.T) {
+	t.Parallel()
+
+	noSchedule := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	noExecute := corev1.Taint{Key: "unreachable", Effect: corev1.TaintEffectNoExecute}
+	preferNoSchedule := corev1.Taint{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}
+
+	tests := []struct {
+		name        string
+		tolerations []corev1.Toleration
+		taints      []corev1.Taint
+		expected    bool
+	}{
+		{
+			name:        "no taints",
+			tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			taints:      nil,
+			expected:    false,
+		},
+		{
+			name:        "only PreferNoSchedule taints",
+			tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			taints:      []corev1.Taint{preferNoSchedule},
+			expected:    false,
+		},
+		{
+			name: "tolerated by key & value",
+			tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			},
+			taints:   []corev1.Taint{noSchedule, preferNoSchedule},
+			expected: true,
+		},
+		{
+			name: "not tolerated by value",
+			tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "cpu", Effect: corev1.TaintEffectNoSchedule},
+			},
+			taints:   []corev1.Taint{noSchedule},
+			expected: false,
+		},
+		{
+			name: "not tolerated by effect",
+			tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+			},
+			taints:   []corev1.Taint{noSchedule},
+			expected: false,
+		},
+		{
+			name:        "tolerated by wildcard",
+			tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			taints:      []corev1.Taint{noSchedule, noExecute},
+			expected:    true,
+		},
+		{
+			name: "only some taints tolerated",
+			tolerations: []corev1.Toleration{
+				{Key: "unreachable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+			},
+			taints:   []corev1.Taint{noSchedule, noExecute},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if actual := toleratesTaints(tt.tolerations, tt.taints); actual != tt.expected {
+				t.Fatalf("expected %t got %t", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolveGraphSubjects(t *testing.T) {
+	t.Parallel()
+
+	objects := newTestObjects(t, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+  namespace: default
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: foo
+  namespace: default
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: foo
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: alice
+- apiGroup: rbac.authorization.k8s.io
+  kind: Group
+  name: ops
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: system:serviceaccount:default:app
+`)
+
+	tests := []struct {
+		name     string
+		opts     ResolveOptions
+		expected []string
+	}{
+		{
+			name: "subjects included",
+			opts: ResolveOptions{IncludeSubjects: true},
+			expected: []string{
+				"RoleBinding/default/foo -[RoleBindingSubject]-> ServiceAccount/default/app",
+				"RoleBinding/default/foo -[RoleBindingSubject]-> subject:Group/ops",
+				"RoleBinding/default/foo -[RoleBindingSubject]-> subject:User/alice",
+			},
+		},
+		{
+			name: "subjects not included",
+			opts: ResolveOptions{},
+			expected: []string{
+				"RoleBinding/default/foo -[RoleBindingSubject]-> ServiceAccount/default/app",
+			},
+		},
+		{
+			name: "subjects & missing objects included",
+			opts: ResolveOptions{IncludeSubjects: true, IncludeMissing: true},
+			expected: []string{
+				"RoleBinding/default/foo -[RoleBindingSubject]-> ServiceAccount/default/app",
+				"RoleBinding/default/foo -[RoleBindingSubject]-> subject:Group/ops",
+				"RoleBinding/default/foo -[RoleBindingSubject]-> subject:User/alice",
+				"missing:Role/default/foo -[RoleBindingRole]-> RoleBinding/default/foo",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodeMap := resolveTestGraph(t, objects, tt.opts)
+			if edges := getTestEdges(nodeMap); !reflect.DeepEqual(edges, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, edges)
+			}
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package graph
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	eventsv1 "k8s.io/api/events/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -15,13 +20,20 @@ import (
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	storagev1beta1 "k8s.io/api/storage/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 )
 
@@ -33,6 +45,14 @@ const (
 	ValidatedPSPAnnotation = "kubernetes.io/psp"
 )
 
+// Well-known API groups.
+const (
+	// Hardcode "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1.SchemeGroupVersion.Group"
+	// as "autoscaling.k8s.io" so we don't need import the entire k8s.io/autoscaler
+	// package.
+	VerticalPodAutoscalerGroupName = "autoscaling.k8s.io"
+)
+
 const (
 	// Kubernetes APIService relationships.
 	RelationshipAPIService Relationship = "APIService"
@@ -52,10 +72,25 @@ const (
 	// Kubernetes CSIStorageCapacity relationships.
 	RelationshipCSIStorageCapacityStorageClass Relationship = "CSIStorageCapacityStorageClass"
 
+	// Kubernetes CustomResourceDefinition relationships.
+	RelationshipCustomResourceDefinition                  Relationship = "CustomResourceDefinition"
+	RelationshipCustomResourceDefinitionConversionService Relationship = "CustomResourceDefinitionConversionService"
+
+	// Kubernetes Endpoints & EndpointSlice relationships.
+	RelationshipEndpointsAddress              Relationship = "EndpointsAddress"
+	RelationshipEndpointsNotReadyAddress      Relationship = "EndpointsNotReadyAddress"
+	RelationshipEndpointsService              Relationship = "EndpointsService"
+	RelationshipEndpointSliceEndpoint         Relationship = "EndpointSliceEndpoint"
+	RelationshipEndpointSliceNotReadyEndpoint Relationship = "EndpointSliceNotReadyEndpoint"
+	RelationshipEndpointSliceService          Relationship = "EndpointSliceService"
+
 	// Kubernetes Event relationships.
 	RelationshipEventRegarding Relationship = "EventRegarding"
 	RelationshipEventRelated   Relationship = "EventRelated"
 
+	// Kubernetes HorizontalPodAutoscaler relationships.
+	RelationshipHorizontalPodAutoscalerScaleTarget Relationship = "HorizontalPodAutoscalerScaleTarget"
+
 	// Kubernetes Ingress & IngressClass relationships.
 	RelationshipIngressClass           Relationship = "IngressClass"
 	RelationshipIngressClassParameters Relationship = "IngressClassParameters"
@@ -64,10 +99,14 @@ const (
 	RelationshipIngressTLSSecret       Relationship = "IngressTLSSecret"
 
 	// Kubernetes MutatingWebhookConfiguration & ValidatingWebhookConfiguration relationships.
+	RelationshipMutatingWebhook             Relationship = "MutatingWebhook"
+	RelationshipValidatingWebhook           Relationship = "ValidatingWebhook"
 	RelationshipWebhookConfigurationService Relationship = "WebhookConfigurationService"
 
 	// Kubernetes RelationshipNetworkPolicy relationships.
-	RelationshipNetworkPolicy Relationship = "NetworkPolicy"
+	RelationshipNetworkPolicy            Relationship = "NetworkPolicy"
+	RelationshipNetworkPolicyEgressPeer  Relationship = "NetworkPolicyEgressPeer"
+	RelationshipNetworkPolicyIngressPeer Relationship = "NetworkPolicyIngressPeer"
 
 	// Kubernetes Owner-Dependent relationships.
 	RelationshipControllerRef Relationship = "ControllerReference"
@@ -75,6 +114,7 @@ const (
 
 	// Kubernetes PersistentVolume & PersistentVolumeClaim relationships.
 	RelationshipPersistentVolumeClaim           Relationship = "PersistentVolumeClaim"
+	RelationshipPersistentVolumeClaimDataSource Relationship = "PersistentVolumeClaimDataSource"
 	RelationshipPersistentVolumeCSIDriver       Relationship = "PersistentVolumeCSIDriver"
 	RelationshipPersistentVolumeCSIDriverSecret Relationship = "PersistentVolumeCSIDriverSecret"
 	RelationshipPersistentVolumeStorageClass    Relationship = "PersistentVolumeStorageClass"
@@ -83,6 +123,9 @@ const (
 	RelationshipPodContainerEnv          Relationship = "PodContainerEnvironment"
 	RelationshipPodImagePullSecret       Relationship = "PodImagePullSecret" //nolint:gosec
 	RelationshipPodNode                  Relationship = "PodNode"
+	RelationshipPodNodeAffinity          Relationship = "PodNodeAffinity"
+	RelationshipPodNodeSelector          Relationship = "PodNodeSelector"
+	RelationshipPodNodeToleration        Relationship = "PodNodeToleration"
 	RelationshipPodPriorityClass         Relationship = "PodPriorityClass"
 	RelationshipPodRuntimeClass          Relationship = "PodRuntimeClass"
 	RelationshipPodSecurityPolicy        Relationship = "PodSecurityPolicy"
@@ -91,6 +134,20 @@ const (
 	RelationshipPodVolumeCSIDriver       Relationship = "PodVolumeCSIDriver"
 	RelationshipPodVolumeCSIDriverSecret Relationship = "PodVolumeCSIDriverSecret" //nolint:gosec
 
+	// Kubernetes workload (CronJob, DaemonSet, Deployment, Job, StatefulSet) pod
+	// template relationships.
+	RelationshipPodTemplateContainerEnv          Relationship = "PodTemplateContainerEnvironment"
+	RelationshipPodTemplateImagePullSecret       Relationship = "PodTemplateImagePullSecret" //nolint:gosec
+	RelationshipPodTemplateNodeAffinity          Relationship = "PodTemplateNodeAffinity"
+	RelationshipPodTemplateNodeSelector          Relationship = "PodTemplateNodeSelector"
+	RelationshipPodTemplateNodeToleration        Relationship = "PodTemplateNodeToleration"
+	RelationshipPodTemplatePriorityClass         Relationship = "PodTemplatePriorityClass"
+	RelationshipPodTemplateRuntimeClass          Relationship = "PodTemplateRuntimeClass"
+	RelationshipPodTemplateServiceAccount        Relationship = "PodTemplateServiceAccount"
+	RelationshipPodTemplateVolume                Relationship = "PodTemplateVolume"
+	RelationshipPodTemplateVolumeCSIDriver       Relationship = "PodTemplateVolumeCSIDriver"
+	RelationshipPodTemplateVolumeCSIDriverSecret Relationship = "PodTemplateVolumeCSIDriverSecret" //nolint:gosec
+
 	// Kubernetes PodDisruptionBudget relationships.
 	RelationshipPodDisruptionBudget Relationship = "PodDisruptionBudget"
 
@@ -109,9 +166,17 @@ const (
 	RelationshipServiceAccountImagePullSecret Relationship = "ServiceAccountImagePullSecret"
 	RelationshipServiceAccountSecret          Relationship = "ServiceAccountSecret"
 
+	// Kubernetes StatefulSet relationships.
+	RelationshipStatefulSetService                   Relationship = "StatefulSetService"
+	RelationshipStatefulSetVolumeClaimTemplateDelete Relationship = "StatefulSetVolumeClaimTemplateDelete"
+	RelationshipStatefulSetVolumeClaimTemplateRetain Relationship = "StatefulSetVolumeClaimTemplateRetain"
+
 	// Kubernetes StorageClass relationships.
 	RelationshipStorageClassProvisioner Relationship = "StorageClassProvisioner"
 
+	// Kubernetes VerticalPodAutoscaler relationships.
+	RelationshipVerticalPodAutoscalerTarget Relationship = "VerticalPodAutoscalerTarget"
+
 	// Kubernetes VolumeAttachment relationships.
 	RelationshipVolumeAttachmentAttacher                    Relationship = "VolumeAttachmentAttacher"
 	RelationshipVolumeAttachmentNode                        Relationship = "VolumeAttachmentNode"
@@ -133,7 +198,7 @@ func getAPIServiceRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipAPIService
 	if svc := apisvc.Spec.Service; svc != nil {
@@ -144,6 +209,13 @@ func getAPIServiceRelationships(n *Node) (*RelationshipMap, error) {
 	return &result, nil
 }
 
+// AggregatedPolicyRule is a policy rule of an aggregated ClusterRole, along
+// with the name of the ClusterRole that contributed the rule.
+type AggregatedPolicyRule struct {
+	rbacv1.PolicyRule
+	Source string `json:"source"`
+}
+
 // getClusterRoleRelationships returns a map of relationships that this
 // ClusterRole has with other objects, based on what was referenced in
 // its manifest.
@@ -157,7 +229,7 @@ func getClusterRoleRelationships(n *Node) (*RelationshipMap, error) {
 	var os ObjectSelector
 	var ols ObjectLabelSelector
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipClusterRoleAggregationRule
 	if ar := cr.AggregationRule; ar != nil {
@@ -172,7 +244,8 @@ func getClusterRoleRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	// RelationshipClusterRolePolicyRule
-	for _, r := range cr.Rules {
+	rules := getEffectivePolicyRules(n, cr.Rules)
+	for _, r := range rules {
 		if podSecurityPolicyMatches(r) {
 			switch len(r.ResourceNames) {
 			case 0:
@@ -186,14 +259,13 @@ func getClusterRoleRelationships(n *Node) (*RelationshipMap, error) {
 			}
 		}
 	}
-
 	return &result, nil
 }
 
 // getClusterRoleBindingRelationships returns a map of relationships that this
 // ClusterRoleBinding has with other objects, based on what was referenced in
 // its manifest.
-//nolint:gocognit
+//nolint:funlen,gocognit
 func getClusterRoleBindingRelationships(n *Node) (*RelationshipMap, error) {
 	var crb rbacv1.ClusterRoleBinding
 	err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.UnstructuredContent(), &crb)
@@ -203,7 +275,7 @@ func getClusterRoleBindingRelationships(n *Node) (*RelationshipMap, error) {
 
 	var os ObjectSelector
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipClusterRoleBindingSubject
 	for _, s := range crb.Subjects {
@@ -215,6 +287,9 @@ func getClusterRoleBindingRelationships(n *Node) (*RelationshipMap, error) {
 			}
 		case rbacv1.GroupKind:
 			if s.APIGroup == rbacv1.GroupName && s.Namespace == "" {
+				// Groups are represented as synthetic nodes
+				ref = ObjectReference{Group: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: s.Name}
+				result.AddDependentByKey(ref.Key(), RelationshipClusterRoleBindingSubject)
 				switch {
 				// All ServiceAccounts in any namespace (authenticated users)
 				case s.Name == user.AllAuthenticated:
@@ -237,6 +312,10 @@ func getClusterRoleBindingRelationships(n *Node) (*RelationshipMap, error) {
 				if err == nil {
 					ref = ObjectReference{Kind: "ServiceAccount", Namespace: ns, Name: sa}
 					result.AddDependentByKey(ref.Key(), RelationshipClusterRoleBindingSubject)
+				} else {
+					// Users that aren't ServiceAccounts are represented as synthetic nodes
+					ref = ObjectReference{Group: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: s.Name}
+					result.AddDependentByKey(ref.Key(), RelationshipClusterRoleBindingSubject)
 				}
 			}
 		}
@@ -262,7 +341,7 @@ func getCSINodeRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipCSINodeDriver
 	for _, d := range csin.Spec.Drivers {
@@ -284,7 +363,7 @@ func getCSIStorageCapacityRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipCSIStorageCapacityStorageClass
 	if sc := csisc.StorageClassName; len(sc) > 0 {
@@ -295,11 +374,107 @@ func getCSIStorageCapacityRelationships(n *Node) (*RelationshipMap, error) {
 	return &result, nil
 }
 
+// getCustomResourceDefinitionRelationships returns a map of relationships that
+// this CustomResourceDefinition has with other objects, based on what was
+// referenced in its manifest.
+func getCustomResourceDefinitionRelationships(n *Node) (*RelationshipMap, error) {
+	var crd apiextensionsv1.CustomResourceDefinition
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.UnstructuredContent(), &crd)
+	if err != nil {
+		return nil, err
+	}
+
+	var os ObjectSelector
+	var ref ObjectReference
+	result := NewRelationshipMap()
+
+	// RelationshipCustomResourceDefinition
+	os = ObjectSelector{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
+	result.AddDependentBySelector(os, RelationshipCustomResourceDefinition)
+
+	// RelationshipCustomResourceDefinitionConversionService
+	if c := crd.Spec.Conversion; c != nil && c.Webhook != nil && c.Webhook.ClientConfig != nil {
+		if svc := c.Webhook.ClientConfig.Service; svc != nil {
+			ref = ObjectReference{Kind: "Service", Namespace: svc.Namespace, Name: svc.Name}
+			result.AddDependencyByKey(ref.Key(), RelationshipCustomResourceDefinitionConversionService)
+		}
+	}
+
+	return &result, nil
+}
+
+// getEndpointsRelationships returns a map of relationships that this Endpoints
+// has with other objects, based on what was referenced in its manifest.
+func getEndpointsRelationships(n *Node) (*RelationshipMap, error) {
+	var ep corev1.Endpoints
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.UnstructuredContent(), &ep)
+	if err != nil {
+		return nil, err
+	}
+
+	var ref ObjectReference
+	ns := ep.Namespace
+	result := NewRelationshipMap()
+
+	// RelationshipEndpointsService
+	//
+	// Endpoints may exist without a Service of the same name (eg. leader
+	// election locks), so the Service is an optional dependency.
+	ref = ObjectReference{Kind: "Service", Name: ep.Name, Namespace: ns}
+	result.AddOptionalDependencyByKey(ref.Key(), RelationshipEndpointsService)
+
+	// RelationshipEndpointsAddress
+	// RelationshipEndpointsNotReadyAddress
+	for _, ss := range ep.Subsets {
+		for _, addr := range ss.Addresses {
+			addTargetRefDependent(&result, addr.TargetRef, RelationshipEndpointsAddress)
+		}
+		for _, addr := range ss.NotReadyAddresses {
+			addTargetRefDependent(&result, addr.TargetRef, RelationshipEndpointsNotReadyAddress)
+		}
+	}
+
+	return &result, nil
+}
+
+// getEndpointSliceRelationships returns a map of relationships that this
+// EndpointSlice has with other objects, based on what was referenced in its
+// manifest.
+func getEndpointSliceRelationships(n *Node) (*RelationshipMap, error) {
+	var eps discoveryv1.EndpointSlice
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.UnstructuredContent(), &eps)
+	if err != nil {
+		return nil, err
+	}
+
+	var ref ObjectReference
+	ns := eps.Namespace
+	result := NewRelationshipMap()
+
+	// RelationshipEndpointSliceService
+	if svc, ok := eps.Labels[discoveryv1.LabelServiceName]; ok && len(svc) != 0 {
+		ref = ObjectReference{Kind: "Service", Name: svc, Namespace: ns}
+		result.AddDependencyByKey(ref.Key(), RelationshipEndpointSliceService)
+	}
+
+	// RelationshipEndpointSliceEndpoint
+	// RelationshipEndpointSliceNotReadyEndpoint
+	for _, e := range eps.Endpoints {
+		r := RelationshipEndpointSliceEndpoint
+		if e.Conditions.Ready != nil && !*e.Conditions.Ready {
+			r = RelationshipEndpointSliceNotReadyEndpoint
+		}
+		addTargetRefDependent(&result, e.TargetRef, r)
+	}
+
+	return &result, nil
+}
+
 // getEventRelationships returns a map of relationships that this Event has with
 // other objects, based on what was referenced in its manifest.
 //nolint:unparam
 func getEventRelationships(n *Node) (*RelationshipMap, error) {
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 	switch n.Group {
 	case corev1.GroupName:
 		// RelationshipEventRegarding
@@ -317,13 +492,31 @@ func getEventRelationships(n *Node) (*RelationshipMap, error) {
 	return &result, nil
 }
 
+// getHorizontalPodAutoscalerRelationships returns a map of relationships that
+// this HorizontalPodAutoscaler has with other objects, based on what was
+// referenced in its manifest.
+func getHorizontalPodAutoscalerRelationships(n *Node) (*RelationshipMap, error) {
+	result := NewRelationshipMap()
+
+	// RelationshipHorizontalPodAutoscalerScaleTarget
+	ref, err := getCrossVersionObjectReference(n, "spec", "scaleTargetRef")
+	if err != nil {
+		return nil, err
+	}
+	if len(ref.Name) > 0 {
+		result.AddDependencyByKey(ref.Key(), RelationshipHorizontalPodAutoscalerScaleTarget)
+	}
+
+	return &result, nil
+}
+
 // getIngressRelationships returns a map of relationships that this Ingress has
 // with other objects, based on what was referenced in its manifest.
 //nolint:funlen,gocognit
 func getIngressRelationships(n *Node) (*RelationshipMap, error) {
 	var ref ObjectReference
 	ns := n.Namespace
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 	switch n.Group {
 	case extensionsv1beta1.GroupName:
 		var ing extensionsv1beta1.Ingress
@@ -433,7 +626,7 @@ func getIngressClassRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipIngressClassParameters
 	if p := ingc.Spec.Parameters; p != nil {
@@ -463,7 +656,7 @@ func getMutatingWebhookConfigurationRelationships(n *Node) (*RelationshipMap, er
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipWebhookConfigurationService
 	for _, wh := range mwc.Webhooks {
@@ -473,9 +666,56 @@ func getMutatingWebhookConfigurationRelationships(n *Node) (*RelationshipMap, er
 		}
 	}
 
+	// RelationshipMutatingWebhook
+	for _, wh := range mwc.Webhooks {
+		err := addWebhookRules(&result, wh.Rules, wh.NamespaceSelector, wh.ObjectSelector, RelationshipMutatingWebhook)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &result, nil
 }
 
+// addWebhookRules adds the objects matched by the provided admission webhook
+// rules & selectors as dependents of the webhook configuration, so that the
+// webhooks intercepting requests for an object are part of its dependencies.
+func addWebhookRules(result *RelationshipMap, rules []admissionregistrationv1.RuleWithOperations, nsSelector, objSelector *metav1.LabelSelector, r Relationship) error {
+	nsSel, objSel := labels.Everything(), labels.Everything()
+	if nsSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(nsSelector)
+		if err != nil {
+			return err
+		}
+		nsSel = selector
+	}
+	if objSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(objSelector)
+		if err != nil {
+			return err
+		}
+		objSel = selector
+	}
+
+	for _, rule := range rules {
+		// Admission webhooks are never called for admission webhook
+		// configurations, so that webhooks can't lock themselves out
+		ors := ObjectRuleSelector{
+			Groups:            rule.APIGroups,
+			ExcludeGroups:     []string{admissionregistrationv1.GroupName},
+			Resources:         rule.Resources,
+			NamespaceSelector: nsSel,
+			Selector:          objSel,
+		}
+		if rule.Scope != nil {
+			ors.Scope = string(*rule.Scope)
+		}
+		result.AddDependentByRuleSelector(ors, r)
+	}
+
+	return nil
+}
+
 // getNetworkPolicyRelationships returns a map of relationships that this
 // NetworkPolicy has with other objects, based on what was referenced in its
 // manifest.
@@ -488,7 +728,7 @@ func getNetworkPolicyRelationships(n *Node) (*RelationshipMap, error) {
 
 	var ols ObjectLabelSelector
 	ns := netpol.Namespace
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipNetworkPolicy
 	selector, err := metav1.LabelSelectorAsSelector(&netpol.Spec.PodSelector)
@@ -498,9 +738,58 @@ func getNetworkPolicyRelationships(n *Node) (*RelationshipMap, error) {
 	ols = ObjectLabelSelector{Kind: "Pod", Namespace: ns, Selector: selector}
 	result.AddDependencyByLabelSelector(ols, RelationshipNetworkPolicy)
 
+	// RelationshipNetworkPolicyIngressPeer
+	for _, rule := range netpol.Spec.Ingress {
+		for _, peer := range rule.From {
+			if err := addNetworkPolicyPeer(&result, peer, ns, RelationshipNetworkPolicyIngressPeer); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// RelationshipNetworkPolicyEgressPeer
+	for _, rule := range netpol.Spec.Egress {
+		for _, peer := range rule.To {
+			if err := addNetworkPolicyPeer(&result, peer, ns, RelationshipNetworkPolicyEgressPeer); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return &result, nil
 }
 
+// addNetworkPolicyPeer adds the pods selected by the provided NetworkPolicy
+// peer as dependents of the NetworkPolicy. Peers are added as dependents so
+// that pods allowed by a NetworkPolicy can be traced back to the pods
+// targeted by the same NetworkPolicy. IPBlock peers are ignored since they
+// don't select any object.
+func addNetworkPolicyPeer(result *RelationshipMap, peer networkingv1.NetworkPolicyPeer, ns string, r Relationship) error {
+	if peer.PodSelector == nil && peer.NamespaceSelector == nil {
+		return nil
+	}
+
+	ols := ObjectLabelSelector{Kind: "Pod", Namespace: ns, Selector: labels.Everything()}
+	if peer.PodSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(peer.PodSelector)
+		if err != nil {
+			return err
+		}
+		ols.Selector = selector
+	}
+	if peer.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+		if err != nil {
+			return err
+		}
+		ols.Namespace = ""
+		ols.NamespaceSelector = selector
+	}
+	result.AddDependentByLabelSelector(ols, r)
+
+	return nil
+}
+
 // getPersistentVolumeRelationships returns a map of relationships that this
 // PersistentVolume has with other objects, based on what was referenced in its
 // manifest.
@@ -513,7 +802,7 @@ func getPersistentVolumeRelationships(n *Node) (*RelationshipMap, error) {
 
 	var ref ObjectReference
 	ns := pv.Namespace
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipPersistentVolumeClaim
 	if pvcRef := pv.Spec.ClaimRef; pvcRef != nil {
@@ -569,7 +858,8 @@ func getPersistentVolumeClaimRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	ns := pvc.Namespace
+	result := NewRelationshipMap()
 
 	// RelationshipPersistentVolumeClaim
 	if pv := pvc.Spec.VolumeName; len(pv) > 0 {
@@ -577,12 +867,33 @@ func getPersistentVolumeClaimRelationships(n *Node) (*RelationshipMap, error) {
 		result.AddDependencyByKey(ref.Key(), RelationshipPersistentVolumeClaim)
 	}
 
+	// RelationshipPersistentVolumeClaimDataSource
+	//
+	// The namespace of "spec.dataSourceRef" isn't part of the PersistentVolumeClaim
+	// type yet, so it's read from the unstructured object instead.
+	if ds := pvc.Spec.DataSource; ds != nil {
+		ref = ObjectReference{Kind: ds.Kind, Name: ds.Name, Namespace: ns}
+		if ds.APIGroup != nil {
+			ref.Group = *ds.APIGroup
+		}
+		result.AddDependencyByKey(ref.Key(), RelationshipPersistentVolumeClaimDataSource)
+	}
+	if dsr := pvc.Spec.DataSourceRef; dsr != nil {
+		ref = ObjectReference{Kind: dsr.Kind, Name: dsr.Name, Namespace: ns}
+		if dsr.APIGroup != nil {
+			ref.Group = *dsr.APIGroup
+		}
+		if dsrNS := n.GetNestedString("spec", "dataSourceRef", "namespace"); len(dsrNS) != 0 {
+			ref.Namespace = dsrNS
+		}
+		result.AddDependencyByKey(ref.Key(), RelationshipPersistentVolumeClaimDataSource)
+	}
+
 	return &result, nil
 }
 
 // getPodRelationships returns a map of relationships that this Pod has with
 // other objects, based on what was referenced in its manifest.
-//nolint:funlen,gocognit
 func getPodRelationships(n *Node) (*RelationshipMap, error) {
 	var pod corev1.Pod
 	err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.UnstructuredContent(), &pod)
@@ -591,22 +902,107 @@ func getPodRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ref ObjectReference
-	ns := pod.Namespace
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipPodContainerEnv
+	// RelationshipPodImagePullSecret
+	// RelationshipPodNodeAffinity
+	// RelationshipPodNodeSelector
+	// RelationshipPodNodeToleration
+	// RelationshipPodPriorityClass
+	// RelationshipPodRuntimeClass
+	// RelationshipPodServiceAccount
+	// RelationshipPodVolume
+	// RelationshipPodVolumeCSIDriver
+	// RelationshipPodVolumeCSIDriverSecret
+	if err := addPodSpecRelationships(&result, &pod.Spec, pod.Namespace, podRelationships); err != nil {
+		return nil, err
+	}
+
+	// RelationshipPodNode
+	ref = ObjectReference{Kind: "Node", Name: pod.Spec.NodeName}
+	result.AddDependencyByKey(ref.Key(), RelationshipPodNode)
+
+	// RelationshipPodSecurityPolicy
+	if psp, ok := pod.Annotations[ValidatedPSPAnnotation]; ok {
+		ref = ObjectReference{Group: policyv1beta1.GroupName, Kind: "PodSecurityPolicy", Name: psp}
+		result.AddDependencyByKey(ref.Key(), RelationshipPodSecurityPolicy)
+	}
+
+	return &result, nil
+}
+
+// podSpecRelationships contains the relationship types used for each kind of
+// object referenced in a PodSpec.
+type podSpecRelationships struct {
+	ContainerEnv          Relationship
+	ImagePullSecret       Relationship
+	NodeAffinity          Relationship
+	NodeSelector          Relationship
+	NodeToleration        Relationship
+	PriorityClass         Relationship
+	RuntimeClass          Relationship
+	ServiceAccount        Relationship
+	Volume                Relationship
+	VolumeCSIDriver       Relationship
+	VolumeCSIDriverSecret Relationship
+}
+
+var (
+	// podRelationships contains the relationship types used for objects
+	// referenced in the PodSpec of a Pod.
+	podRelationships = podSpecRelationships{
+		ContainerEnv:          RelationshipPodContainerEnv,
+		ImagePullSecret:       RelationshipPodImagePullSecret,
+		NodeAffinity:          RelationshipPodNodeAffinity,
+		NodeSelector:          RelationshipPodNodeSelector,
+		NodeToleration:        RelationshipPodNodeToleration,
+		PriorityClass:         RelationshipPodPriorityClass,
+		RuntimeClass:          RelationshipPodRuntimeClass,
+		ServiceAccount:        RelationshipPodServiceAccount,
+		Volume:                RelationshipPodVolume,
+		VolumeCSIDriver:       RelationshipPodVolumeCSIDriver,
+		VolumeCSIDriverSecret: RelationshipPodVolumeCSIDriverSecret,
+	}
+	// podTemplateRelationships contains the relationship types used for objects
+	// referenced in the PodSpec of a workload's pod template.
+	podTemplateRelationships = podSpecRelationships{
+		ContainerEnv:          RelationshipPodTemplateContainerEnv,
+		ImagePullSecret:       RelationshipPodTemplateImagePullSecret,
+		NodeAffinity:          RelationshipPodTemplateNodeAffinity,
+		NodeSelector:          RelationshipPodTemplateNodeSelector,
+		NodeToleration:        RelationshipPodTemplateNodeToleration,
+		PriorityClass:         RelationshipPodTemplatePriorityClass,
+		RuntimeClass:          RelationshipPodTemplateRuntimeClass,
+		ServiceAccount:        RelationshipPodTemplateServiceAccount,
+		Volume:                RelationshipPodTemplateVolume,
+		VolumeCSIDriver:       RelationshipPodTemplateVolumeCSIDriver,
+		VolumeCSIDriverSecret: RelationshipPodTemplateVolumeCSIDriverSecret,
+	}
+)
+
+// addPodSpecRelationships adds the relationships to all objects referenced in
+// the provided PodSpec into the relationship map, using the provided
+// relationship types.
+//
+//nolint:funlen,gocognit,gocyclo
+func addPodSpecRelationships(result *RelationshipMap, spec *corev1.PodSpec, ns string, rels podSpecRelationships) error {
+	var ols ObjectLabelSelector
+	var ref ObjectReference
+
+	// Container environment
 	var cList []corev1.Container
-	cList = append(cList, pod.Spec.InitContainers...)
-	cList = append(cList, pod.Spec.Containers...)
+	cList = append(cList, spec.InitContainers...)
+	cList = append(cList, spec.Containers...)
 	for _, c := range cList {
 		for _, env := range c.EnvFrom {
 			switch {
 			case env.ConfigMapRef != nil:
 				ref = ObjectReference{Kind: "ConfigMap", Name: env.ConfigMapRef.Name, Namespace: ns}
-				result.AddDependencyByKey(ref.Key(), RelationshipPodContainerEnv)
+				addPodSpecDependency(result, ref, env.ConfigMapRef.Optional, rels.ContainerEnv)
 			case env.SecretRef != nil:
 				ref = ObjectReference{Kind: "Secret", Name: env.SecretRef.Name, Namespace: ns}
-				result.AddDependencyByKey(ref.Key(), RelationshipPodContainerEnv)
+				addPodSpecDependency(result, ref, env.SecretRef.Optional, rels.ContainerEnv)
 			}
 		}
 		for _, env := range c.Env {
@@ -616,86 +1012,195 @@ func getPodRelationships(n *Node) (*RelationshipMap, error) {
 			switch {
 			case env.ValueFrom.ConfigMapKeyRef != nil:
 				ref = ObjectReference{Kind: "ConfigMap", Name: env.ValueFrom.ConfigMapKeyRef.Name, Namespace: ns}
-				result.AddDependencyByKey(ref.Key(), RelationshipPodContainerEnv)
+				addPodSpecDependency(result, ref, env.ValueFrom.ConfigMapKeyRef.Optional, rels.ContainerEnv)
 			case env.ValueFrom.SecretKeyRef != nil:
 				ref = ObjectReference{Kind: "Secret", Name: env.ValueFrom.SecretKeyRef.Name, Namespace: ns}
-				result.AddDependencyByKey(ref.Key(), RelationshipPodContainerEnv)
+				addPodSpecDependency(result, ref, env.ValueFrom.SecretKeyRef.Optional, rels.ContainerEnv)
 			}
 		}
 	}
 
-	// RelationshipPodImagePullSecret
-	for _, ips := range pod.Spec.ImagePullSecrets {
+	// Image pull secrets
+	for _, ips := range spec.ImagePullSecrets {
 		ref = ObjectReference{Kind: "Secret", Name: ips.Name, Namespace: ns}
-		result.AddDependencyByKey(ref.Key(), RelationshipPodImagePullSecret)
+		result.AddDependencyByKey(ref.Key(), rels.ImagePullSecret)
 	}
 
-	// RelationshipPodNode
-	ref = ObjectReference{Kind: "Node", Name: pod.Spec.NodeName}
-	result.AddDependencyByKey(ref.Key(), RelationshipPodNode)
+	// Node affinity
+	if a := spec.Affinity; a != nil && a.NodeAffinity != nil {
+		if req := a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; req != nil {
+			for _, term := range req.NodeSelectorTerms {
+				olsList, err := getNodeSelectorTermSelectors(term)
+				if err != nil {
+					klog.V(4).Infof("Skipping invalid node selector term of pod spec in namespace \"%s\": %s", ns, err)
+					continue
+				}
+				for _, s := range olsList {
+					result.AddDependencyByLabelSelector(s, rels.NodeAffinity)
+				}
+			}
+		}
+	}
 
-	// RelationshipPodPriorityClass
-	if pc := pod.Spec.PriorityClassName; len(pc) != 0 {
-		ref = ObjectReference{Group: schedulingv1.GroupName, Kind: "PriorityClass", Name: pc}
-		result.AddDependencyByKey(ref.Key(), RelationshipPodPriorityClass)
+	// Node selector
+	if len(spec.NodeSelector) != 0 {
+		selector, err := labels.ValidatedSelectorFromSet(labels.Set(spec.NodeSelector))
+		if err != nil {
+			return err
+		}
+		ols = ObjectLabelSelector{Kind: "Node", Selector: selector}
+		result.AddDependencyByLabelSelector(ols, rels.NodeSelector)
 	}
 
-	// RelationshipPodRuntimeClass
-	if rc := pod.Spec.RuntimeClassName; rc != nil && len(*rc) != 0 {
-		ref = ObjectReference{Group: nodev1.GroupName, Kind: "RuntimeClass", Name: *rc}
-		result.AddDependencyByKey(ref.Key(), RelationshipPodRuntimeClass)
+	// Node tolerations
+	if len(spec.Tolerations) != 0 {
+		ols = ObjectLabelSelector{Kind: "Node", Selector: labels.Everything(), Tolerations: spec.Tolerations}
+		result.AddDependencyByLabelSelector(ols, rels.NodeToleration)
 	}
 
-	// RelationshipPodSecurityPolicy
-	if psp, ok := pod.Annotations[ValidatedPSPAnnotation]; ok {
-		ref = ObjectReference{Group: policyv1beta1.GroupName, Kind: "PodSecurityPolicy", Name: psp}
-		result.AddDependencyByKey(ref.Key(), RelationshipPodSecurityPolicy)
+	// Priority class
+	if pc := spec.PriorityClassName; len(pc) != 0 {
+		ref = ObjectReference{Group: schedulingv1.GroupName, Kind: "PriorityClass", Name: pc}
+		result.AddDependencyByKey(ref.Key(), rels.PriorityClass)
 	}
 
-	// RelationshipPodServiceAccount
-	if sa := pod.Spec.ServiceAccountName; len(sa) != 0 {
+	// Runtime class
+	if rc := spec.RuntimeClassName; rc != nil && len(*rc) != 0 {
+		ref = ObjectReference{Group: nodev1.GroupName, Kind: "RuntimeClass", Name: *rc}
+		result.AddDependencyByKey(ref.Key(), rels.RuntimeClass)
+	}
+
+	// Service account
+	if sa := spec.ServiceAccountName; len(sa) != 0 {
 		ref = ObjectReference{Kind: "ServiceAccount", Name: sa, Namespace: ns}
-		result.AddDependencyByKey(ref.Key(), RelationshipPodServiceAccount)
+		result.AddDependencyByKey(ref.Key(), rels.ServiceAccount)
 	}
 
-	// RelationshipPodVolume
-	// RelationshipPodVolumeCSIDriver
-	// RelationshipPodVolumeCSIDriverSecret
-	for _, v := range pod.Spec.Volumes {
+	// Volumes
+	for _, v := range spec.Volumes {
 		vs := v.VolumeSource
 		switch {
 		case vs.ConfigMap != nil:
 			ref = ObjectReference{Kind: "ConfigMap", Name: vs.ConfigMap.Name, Namespace: ns}
-			result.AddDependencyByKey(ref.Key(), RelationshipPodVolume)
+			addPodSpecDependency(result, ref, vs.ConfigMap.Optional, rels.Volume)
 		case vs.CSI != nil:
 			csi := vs.CSI
 			ref = ObjectReference{Group: storagev1.GroupName, Kind: "CSIDriver", Name: csi.Driver}
-			result.AddDependencyByKey(ref.Key(), RelationshipPodVolumeCSIDriver)
+			result.AddDependencyByKey(ref.Key(), rels.VolumeCSIDriver)
 			if nps := csi.NodePublishSecretRef; nps != nil {
 				ref = ObjectReference{Kind: "Secret", Name: nps.Name, Namespace: ns}
-				result.AddDependencyByKey(ref.Key(), RelationshipPodVolumeCSIDriverSecret)
+				result.AddDependencyByKey(ref.Key(), rels.VolumeCSIDriverSecret)
 			}
 		case vs.PersistentVolumeClaim != nil:
 			ref = ObjectReference{Kind: "PersistentVolumeClaim", Name: vs.PersistentVolumeClaim.ClaimName, Namespace: ns}
-			result.AddDependencyByKey(ref.Key(), RelationshipPodVolume)
+			result.AddDependencyByKey(ref.Key(), rels.Volume)
 		case vs.Projected != nil:
 			for _, src := range vs.Projected.Sources {
 				switch {
 				case src.ConfigMap != nil:
 					ref = ObjectReference{Kind: "ConfigMap", Name: src.ConfigMap.Name, Namespace: ns}
-					result.AddDependencyByKey(ref.Key(), RelationshipPodVolume)
+					addPodSpecDependency(result, ref, src.ConfigMap.Optional, rels.Volume)
 				case src.Secret != nil:
 					ref = ObjectReference{Kind: "Secret", Name: src.Secret.Name, Namespace: ns}
-					result.AddDependencyByKey(ref.Key(), RelationshipPodVolume)
+					addPodSpecDependency(result, ref, src.Secret.Optional, rels.Volume)
 				}
 			}
 		case vs.Secret != nil:
 			ref = ObjectReference{Kind: "Secret", Name: vs.Secret.SecretName, Namespace: ns}
-			result.AddDependencyByKey(ref.Key(), RelationshipPodVolume)
+			addPodSpecDependency(result, ref, vs.Secret.Optional, rels.Volume)
 		}
 	}
 
-	return &result, nil
+	return nil
+}
+
+// addPodSpecDependency adds the provided reference as a dependency into the
+// relationship map, as an optional dependency if the PodSpec marks it as
+// optional (ie. ConfigMaps & Secrets referenced with "optional: true").
+func addPodSpecDependency(result *RelationshipMap, ref ObjectReference, optional *bool, r Relationship) {
+	if optional != nil && *optional {
+		result.AddOptionalDependencyByKey(ref.Key(), r)
+		return
+	}
+	result.AddDependencyByKey(ref.Key(), r)
+}
+
+// getNodeSelectorTermSelectors returns the selectors matching the Nodes
+// selected by the provided node selector term. Multiple selectors are returned
+// if the term only matches Nodes with specific names, since field selectors
+// can't match a field against a set of values. A term without any requirements
+// doesn't match any Node.
+//
+//nolint:gocognit,gocyclo
+func getNodeSelectorTermSelectors(term corev1.NodeSelectorTerm) ([]ObjectLabelSelector, error) {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return nil, nil
+	}
+
+	selector := labels.NewSelector()
+	for _, expr := range term.MatchExpressions {
+		var op selection.Operator
+		switch expr.Operator {
+		case corev1.NodeSelectorOpIn:
+			op = selection.In
+		case corev1.NodeSelectorOpNotIn:
+			op = selection.NotIn
+		case corev1.NodeSelectorOpExists:
+			op = selection.Exists
+		case corev1.NodeSelectorOpDoesNotExist:
+			op = selection.DoesNotExist
+		case corev1.NodeSelectorOpGt:
+			op = selection.GreaterThan
+		case corev1.NodeSelectorOpLt:
+			op = selection.LessThan
+		default:
+			return nil, fmt.Errorf("unsupported node selector operator \"%s\"", expr.Operator)
+		}
+		r, err := labels.NewRequirement(expr.Key, op, expr.Values)
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*r)
+	}
+
+	// Only the "metadata.name" field is supported by node selector terms
+	var names sets.String
+	excludedNames := sets.NewString()
+	for _, f := range term.MatchFields {
+		if f.Key != "metadata.name" {
+			return nil, fmt.Errorf("unsupported node selector field \"%s\"", f.Key)
+		}
+		switch f.Operator {
+		case corev1.NodeSelectorOpIn:
+			if names == nil {
+				names = sets.NewString(f.Values...)
+			} else {
+				names = names.Intersection(sets.NewString(f.Values...))
+			}
+		case corev1.NodeSelectorOpNotIn:
+			excludedNames.Insert(f.Values...)
+		default:
+			return nil, fmt.Errorf("unsupported node selector field operator \"%s\"", f.Operator)
+		}
+	}
+
+	if names == nil {
+		ols := ObjectLabelSelector{Kind: "Node", Selector: selector}
+		if excludedNames.Len() > 0 {
+			var fsList []fields.Selector
+			for _, name := range excludedNames.List() {
+				fsList = append(fsList, fields.OneTermNotEqualSelector("metadata.name", name))
+			}
+			ols.FieldSelector = fields.AndSelectors(fsList...)
+		}
+		return []ObjectLabelSelector{ols}, nil
+	}
+	var result []ObjectLabelSelector
+	for _, name := range names.Difference(excludedNames).List() {
+		fs := fields.OneTermEqualSelector("metadata.name", name)
+		result = append(result, ObjectLabelSelector{Kind: "Node", Selector: selector, FieldSelector: fs})
+	}
+	return result, nil
 }
 
 // getPodDisruptionBudgetRelationships returns a map of relationships that this
@@ -710,7 +1215,7 @@ func getPodDisruptionBudgetRelationships(n *Node) (*RelationshipMap, error) {
 
 	var ols ObjectLabelSelector
 	ns := pdb.Namespace
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipPodDisruptionBudget
 	if s := pdb.Spec.Selector; s != nil {
@@ -736,7 +1241,7 @@ func getPodSecurityPolicyRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipPodSecurityPolicyAllowedCSIDriver
 	for _, csi := range psp.Spec.AllowedCSIDrivers {
@@ -771,7 +1276,7 @@ func getRoleRelationships(n *Node) (*RelationshipMap, error) {
 
 	var os ObjectSelector
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipRolePolicyRule
 	for _, r := range ro.Rules {
@@ -788,7 +1293,6 @@ func getRoleRelationships(n *Node) (*RelationshipMap, error) {
 			}
 		}
 	}
-
 	return &result, nil
 }
 
@@ -806,7 +1310,7 @@ func getRoleBindingRelationships(n *Node) (*RelationshipMap, error) {
 	var os ObjectSelector
 	var ref ObjectReference
 	ns := rb.Namespace
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipRoleBindingSubject
 	for _, s := range rb.Subjects {
@@ -818,6 +1322,9 @@ func getRoleBindingRelationships(n *Node) (*RelationshipMap, error) {
 			}
 		case rbacv1.GroupKind:
 			if s.APIGroup == rbacv1.GroupName && s.Namespace == "" {
+				// Groups are represented as synthetic nodes
+				ref = ObjectReference{Group: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: s.Name}
+				result.AddDependentByKey(ref.Key(), RelationshipRoleBindingSubject)
 				switch {
 				// All ServiceAccounts in the RoleBinding namespace (authenticated users)
 				case s.Name == user.AllAuthenticated:
@@ -840,6 +1347,10 @@ func getRoleBindingRelationships(n *Node) (*RelationshipMap, error) {
 				if err == nil {
 					ref = ObjectReference{Kind: "ServiceAccount", Namespace: ns, Name: sa}
 					result.AddDependentByKey(ref.Key(), RelationshipRoleBindingSubject)
+				} else {
+					// Users that aren't ServiceAccounts are represented as synthetic nodes
+					ref = ObjectReference{Group: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: s.Name}
+					result.AddDependentByKey(ref.Key(), RelationshipRoleBindingSubject)
 				}
 			}
 		}
@@ -871,7 +1382,7 @@ func getRuntimeClassRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ols ObjectLabelSelector
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipRuntimeClass
 	if s := rc.Scheduling; s != nil {
@@ -898,7 +1409,7 @@ func getServiceRelationships(n *Node) (*RelationshipMap, error) {
 
 	var ols ObjectLabelSelector
 	ns := svc.Namespace
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipService
 	selector, err := labels.ValidatedSelectorFromSet(labels.Set(svc.Spec.Selector))
@@ -923,7 +1434,7 @@ func getServiceAccountRelationships(n *Node) (*RelationshipMap, error) {
 
 	var ref ObjectReference
 	ns := sa.Namespace
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipServiceAccountImagePullSecret
 	for _, s := range sa.ImagePullSecrets {
@@ -940,6 +1451,53 @@ func getServiceAccountRelationships(n *Node) (*RelationshipMap, error) {
 	return &result, nil
 }
 
+// getStatefulSetRelationships returns a map of relationships that this
+// StatefulSet has with other objects, based on what was referenced in its
+// manifest.
+func getStatefulSetRelationships(n *Node) (*RelationshipMap, error) {
+	var sts appsv1.StatefulSet
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.UnstructuredContent(), &sts)
+	if err != nil {
+		return nil, err
+	}
+
+	var ref ObjectReference
+	ns := sts.Namespace
+	result, err := getWorkloadRelationships(n)
+	if err != nil {
+		return nil, err
+	}
+
+	// RelationshipStatefulSetService
+	if svc := sts.Spec.ServiceName; len(svc) != 0 {
+		ref = ObjectReference{Kind: "Service", Name: svc, Namespace: ns}
+		result.AddDependencyByKey(ref.Key(), RelationshipStatefulSetService)
+	}
+
+	// RelationshipStatefulSetVolumeClaimTemplateDelete
+	// RelationshipStatefulSetVolumeClaimTemplateRetain
+	//
+	// PVCs created from volume claim templates are named
+	// "<template>-<statefulset>-<ordinal>", so we select the existing PVCs of
+	// every ordinal, including PVCs retained after scaling the StatefulSet down.
+	// Whether the PVCs are deleted along with the StatefulSet depends on its PVC
+	// retention policy.
+	r := RelationshipStatefulSetVolumeClaimTemplateRetain
+	if p := sts.Spec.PersistentVolumeClaimRetentionPolicy; p != nil && p.WhenDeleted == appsv1.DeletePersistentVolumeClaimRetentionPolicyType {
+		r = RelationshipStatefulSetVolumeClaimTemplateDelete
+	}
+	for _, vct := range sts.Spec.VolumeClaimTemplates {
+		os := ObjectSelector{
+			Kind:              "PersistentVolumeClaim",
+			Namespaces:        sets.NewString(ns),
+			OrdinalNamePrefix: fmt.Sprintf("%s-%s-", vct.Name, sts.Name),
+		}
+		result.AddDependentBySelector(os, r)
+	}
+
+	return result, nil
+}
+
 // getStorageClassRelationships returns a map of relationships that this
 // StorageClass has with other objects, based on what was referenced in its
 // manifest.
@@ -951,7 +1509,7 @@ func getStorageClassRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipStorageClassProvisioner (external provisioners only)
 	if p := sc.Provisioner; len(p) > 0 && !strings.HasPrefix(p, "kubernetes.io/") {
@@ -973,7 +1531,7 @@ func getValidatingWebhookConfigurationRelationships(n *Node) (*RelationshipMap,
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipWebhookConfigurationService
 	for _, wh := range vwc.Webhooks {
@@ -983,6 +1541,14 @@ func getValidatingWebhookConfigurationRelationships(n *Node) (*RelationshipMap,
 		}
 	}
 
+	// RelationshipValidatingWebhook
+	for _, wh := range vwc.Webhooks {
+		err := addWebhookRules(&result, wh.Rules, wh.NamespaceSelector, wh.ObjectSelector, RelationshipValidatingWebhook)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &result, nil
 }
 
@@ -998,7 +1564,7 @@ func getVolumeAttachmentRelationships(n *Node) (*RelationshipMap, error) {
 	}
 
 	var ref ObjectReference
-	result := newRelationshipMap()
+	result := NewRelationshipMap()
 
 	// RelationshipVolumeAttachmentAttacher
 	if a := va.Spec.Attacher; len(a) > 0 {
@@ -1061,6 +1627,303 @@ func getVolumeAttachmentRelationships(n *Node) (*RelationshipMap, error) {
 	return &result, nil
 }
 
+// getVerticalPodAutoscalerRelationships returns a map of relationships that
+// this VerticalPodAutoscaler has with other objects, based on what was
+// referenced in its manifest.
+func getVerticalPodAutoscalerRelationships(n *Node) (*RelationshipMap, error) {
+	result := NewRelationshipMap()
+
+	// RelationshipVerticalPodAutoscalerTarget
+	ref, err := getCrossVersionObjectReference(n, "spec", "targetRef")
+	if err != nil {
+		return nil, err
+	}
+	if len(ref.Name) > 0 {
+		result.AddDependencyByKey(ref.Key(), RelationshipVerticalPodAutoscalerTarget)
+	}
+
+	return &result, nil
+}
+
+// getWorkloadRelationships returns a map of relationships that this workload
+// (ie. CronJob, DaemonSet, Deployment, Job or StatefulSet) has with other
+// objects, based on what was referenced in its pod template. This ensures that
+// relationships are found even if the workload has no running pods.
+func getWorkloadRelationships(n *Node) (*RelationshipMap, error) {
+	fields := []string{"spec", "template"}
+	if n.Group == batchv1.GroupName && n.Kind == "CronJob" {
+		fields = []string{"spec", "jobTemplate", "spec", "template"}
+	}
+	m, found, err := unstructuredv1.NestedMap(n.UnstructuredContent(), fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewRelationshipMap()
+	if !found {
+		return &result, nil
+	}
+	var tmpl corev1.PodTemplateSpec
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(m, &tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	// RelationshipPodTemplateContainerEnv
+	// RelationshipPodTemplateImagePullSecret
+	// RelationshipPodTemplateNodeAffinity
+	// RelationshipPodTemplateNodeSelector
+	// RelationshipPodTemplateNodeToleration
+	// RelationshipPodTemplatePriorityClass
+	// RelationshipPodTemplateRuntimeClass
+	// RelationshipPodTemplateServiceAccount
+	// RelationshipPodTemplateVolume
+	// RelationshipPodTemplateVolumeCSIDriver
+	// RelationshipPodTemplateVolumeCSIDriverSecret
+	if err := addPodSpecRelationships(&result, &tmpl.Spec, n.Namespace, podTemplateRelationships); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// addTargetRefDependent adds the object referenced by the provided endpoint
+// target reference as a dependent into the relationship map. The object is
+// referenced by its UID if available, otherwise by its kind, namespace & name.
+func addTargetRefDependent(result *RelationshipMap, ref *corev1.ObjectReference, r Relationship) {
+	if ref == nil {
+		return
+	}
+	if len(ref.UID) != 0 {
+		result.AddDependentByUID(ref.UID, r)
+		return
+	}
+	if len(ref.Kind) == 0 || len(ref.Name) == 0 {
+		return
+	}
+	gv, _ := schema.ParseGroupVersion(ref.APIVersion)
+	key := ObjectReference{Group: gv.Group, Kind: ref.Kind, Name: ref.Name, Namespace: ref.Namespace}
+	result.AddDependentByKey(key.Key(), r)
+}
+
+// getCrossVersionObjectReference returns a reference to the object referenced
+// by the CrossVersionObjectReference at the provided fields of the object, which
+// is expected to be in the same namespace as the object. Returns an empty
+// reference if there is no object referenced.
+func getCrossVersionObjectReference(n *Node, fields ...string) (ObjectReference, error) {
+	kind := n.GetNestedString(append(fields, "kind")...)
+	name := n.GetNestedString(append(fields, "name")...)
+	if len(kind) == 0 || len(name) == 0 {
+		return ObjectReference{}, nil
+	}
+	gv, err := schema.ParseGroupVersion(n.GetNestedString(append(fields, "apiVersion")...))
+	if err != nil {
+		return ObjectReference{}, err
+	}
+	return ObjectReference{Group: gv.Group, Kind: kind, Namespace: n.Namespace, Name: name}, nil
+}
+
+// resolvePolicyRuleRelationships populates the relationships between every
+// RoleBinding & ClusterRoleBinding in the provided node map & the objects that
+// the policy rules of their Role or ClusterRole allow any of the provided verbs
+// to be performed on. Rules that match all API groups or resources are skipped
+// unless includeWildcards is true.
+//
+// Relationships are added to the bindings rather than to the roles, since a
+// ClusterRole may be bound in several namespaces & each binding only grants
+// access to its own set of objects. RoleBindings only grant access to objects
+// in their own namespace, even if they reference a ClusterRole, while
+// ClusterRoleBindings grant access to all objects.
+//
+//nolint:funlen,gocognit
+func resolvePolicyRuleRelationships(nodeMap map[types.UID]*Node, verbs []string, includeWildcards bool) {
+	verbSet := sets.NewString(verbs...)
+	for uid, role := range nodeMap {
+		if uid != role.UID || role.Missing || role.Group != rbacv1.GroupName {
+			continue
+		}
+
+		var r Relationship
+		switch role.Kind {
+		case "ClusterRole":
+			r = RelationshipClusterRolePolicyRule
+		case "Role":
+			r = RelationshipRolePolicyRule
+		default:
+			continue
+		}
+		// ClusterRoleBindings are checked by their kind since they reference
+		// ClusterRoles with the same relationship type as RoleBindings
+		var bindings []*Node
+		for bindingUID, rset := range role.Dependents {
+			_, isClusterRoleBindingRole := rset[RelationshipClusterRoleBindingRole]
+			_, isRoleBindingRole := rset[RelationshipRoleBindingRole]
+			if binding, ok := nodeMap[bindingUID]; ok && (isClusterRoleBindingRole || isRoleBindingRole) {
+				bindings = append(bindings, binding)
+			}
+		}
+		if len(bindings) == 0 {
+			continue
+		}
+
+		rules, err := getPolicyRules(role)
+		if err != nil {
+			klog.V(4).Infof("Failed to get policy rules of %s named \"%s\": %s", strings.ToLower(role.Kind), role.Name, err)
+			continue
+		}
+		var matchingRules []rbacv1.PolicyRule
+		for _, rule := range rules {
+			if !verbSet.HasAny(rule.Verbs...) && !sets.NewString(rule.Verbs...).Has(rbacv1.VerbAll) {
+				continue
+			}
+			isWildcard := sets.NewString(rule.APIGroups...).Has(rbacv1.APIGroupAll) || sets.NewString(rule.Resources...).Has(rbacv1.ResourceAll)
+			if isWildcard && !includeWildcards {
+				continue
+			}
+			matchingRules = append(matchingRules, rule)
+		}
+		if len(matchingRules) == 0 {
+			continue
+		}
+
+		for uid, n := range nodeMap {
+			if uid != n.UID || n.Missing || n.Synthetic || n.UID == role.UID {
+				continue
+			}
+			matched := false
+			for _, rule := range matchingRules {
+				if policyRuleMatches(rule, n) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+			for _, binding := range bindings {
+				if binding.Kind != "ClusterRoleBinding" && (!n.Namespaced || n.Namespace != binding.Namespace) {
+					continue
+				}
+				binding.AddDependency(n.UID, r)
+				n.AddDependent(binding.UID, r)
+			}
+		}
+	}
+}
+
+// getEffectivePolicyRules returns the effective policy rules of the provided
+// node, which are its aggregated rules if it's an aggregated ClusterRole,
+// otherwise the provided rules.
+func getEffectivePolicyRules(n *Node, rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	if n.AggregatedRules == nil {
+		return rules
+	}
+	result := make([]rbacv1.PolicyRule, 0, len(n.AggregatedRules))
+	for _, r := range n.AggregatedRules {
+		result = append(result, r.PolicyRule)
+	}
+	return result
+}
+
+// isSubjectReference returns true if the provided object reference refers to a
+// user or group that is a subject of role bindings.
+func isSubjectReference(ref ObjectReference) bool {
+	if ref.Group != rbacv1.GroupName || len(ref.Namespace) != 0 {
+		return false
+	}
+	return ref.Kind == rbacv1.UserKind || ref.Kind == rbacv1.GroupKind
+}
+
+// resolveAggregatedRules computes the effective policy rules of every
+// aggregated ClusterRole in the provided node map, the same way the
+// "clusterrole-aggregation" controller does. Rules of selected ClusterRoles
+// that are also aggregated are resolved recursively, so each rule is attributed
+// to the ClusterRole that declared it.
+func resolveAggregatedRules(nodeMap map[types.UID]*Node) {
+	type clusterRole struct {
+		node      *Node
+		rules     []rbacv1.PolicyRule
+		selectors []labels.Selector
+	}
+	roles := []*clusterRole{}
+	for _, n := range nodeMap {
+		if n.Group != rbacv1.GroupName || n.Kind != "ClusterRole" {
+			continue
+		}
+		var cr rbacv1.ClusterRole
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.UnstructuredContent(), &cr); err != nil {
+			continue
+		}
+		role := &clusterRole{node: n, rules: cr.Rules}
+		if ar := cr.AggregationRule; ar != nil {
+			role.selectors = []labels.Selector{}
+			for ix := range ar.ClusterRoleSelectors {
+				selector, err := metav1.LabelSelectorAsSelector(&ar.ClusterRoleSelectors[ix])
+				if err != nil {
+					continue
+				}
+				role.selectors = append(role.selectors, selector)
+			}
+		}
+		roles = append(roles, role)
+	}
+	// The aggregation controller adds rules from selected ClusterRoles in the
+	// order of their names
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].node.Name < roles[j].node.Name
+	})
+
+	resolved := map[types.UID][]AggregatedPolicyRule{}
+	var resolveFn func(role *clusterRole) []AggregatedPolicyRule
+	resolveFn = func(role *clusterRole) []AggregatedPolicyRule {
+		if role.selectors == nil {
+			result := make([]AggregatedPolicyRule, 0, len(role.rules))
+			for _, r := range role.rules {
+				result = append(result, AggregatedPolicyRule{PolicyRule: r, Source: role.node.Name})
+			}
+			return result
+		}
+		// Guard against aggregation cycles, ClusterRoles that are still being
+		// resolved don't contribute any rules
+		if rules, ok := resolved[role.node.UID]; ok {
+			return rules
+		}
+		resolved[role.node.UID] = nil
+
+		result := []AggregatedPolicyRule{}
+		for _, selector := range role.selectors {
+			for _, r := range roles {
+				if r.node.UID == role.node.UID || !selector.Matches(labels.Set(r.node.GetLabels())) {
+					continue
+				}
+				for _, rule := range resolveFn(r) {
+					if !aggregatedRuleExists(result, rule.PolicyRule) {
+						result = append(result, rule)
+					}
+				}
+			}
+		}
+		resolved[role.node.UID] = result
+		return result
+	}
+	for _, role := range roles {
+		if role.selectors != nil {
+			role.node.AggregatedRules = resolveFn(role)
+		}
+	}
+}
+
+// aggregatedRuleExists returns true if the provided policy rule is one of the
+// provided aggregated rules.
+func aggregatedRuleExists(rules []AggregatedPolicyRule, rule rbacv1.PolicyRule) bool {
+	for _, r := range rules {
+		if equality.Semantic.DeepEqual(r.PolicyRule, rule) {
+			return true
+		}
+	}
+	return false
+}
+
 // podSecurityPolicyMatches returns true if PolicyRule matches "policy" APIGroup,
 // "podsecuritypolicies" resource & "use" verb.
 func podSecurityPolicyMatches(r rbacv1.PolicyRule) bool {
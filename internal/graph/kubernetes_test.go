@@ -0,0 +1,634 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetNodeSelectorTermSelectors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		term        corev1.NodeSelectorTerm
+		expectedErr bool
+		// expected contains the label & field selectors of each selector, in the
+		// "<label selector>|<field selector>" form
+		expected []string
+	}{
+		{
+			name:     "empty term",
+			term:     corev1.NodeSelectorTerm{},
+			expected: nil,
+		},
+		{
+			name: "match expressions",
+			term: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b", "a"}},
+					{Key: "disk", Operator: corev1.NodeSelectorOpExists},
+					{Key: "cpu", Operator: corev1.NodeSelectorOpGt, Values: []string{"4"}},
+				},
+			},
+			expected: []string{"cpu>4,disk,zone in (a,b)|"},
+		},
+		{
+			name: "match fields with names",
+			term: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "disk", Operator: corev1.NodeSelectorOpExists},
+				},
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"n1", "n2", "n3"}},
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"n2", "n3", "n4"}},
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"n3"}},
+				},
+			},
+			expected: []string{"disk|metadata.name=n2"},
+		},
+		{
+			name: "match fields with excluded names",
+			term: corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"n2", "n1"}},
+				},
+			},
+			expected: []string{"|metadata.name!=n1,metadata.name!=n2"},
+		},
+		{
+			name: "match fields without any name",
+			term: corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"n1"}},
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"n1"}},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "unsupported operator",
+			term: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "zone", Operator: "Matches", Values: []string{"a"}},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid match expression",
+			term: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "cpu", Operator: corev1.NodeSelectorOpGt, Values: []string{"4", "8"}},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unsupported field",
+			term: corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.namespace", Operator: corev1.NodeSelectorOpIn, Values: []string{"default"}},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unsupported field operator",
+			term: corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpExists},
+				},
+			},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			olsList, err := getNodeSelectorTermSelectors(tt.term)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get selectors: %v", err)
+			}
+			var selectors []string
+			for _, ols := range olsList {
+				if ols.Kind != "Node" {
+					t.Fatalf("expected kind \"Node\" got \"%s\"", ols.Kind)
+				}
+				s := ols.Selector.String() + "|"
+				if ols.FieldSelector != nil {
+					s += ols.FieldSelector.String()
+				}
+				selectors = append(selectors, s)
+			}
+			if !reflect.DeepEqual(selectors, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, selectors)
+			}
+		})
+	}
+}
+
+func TestResolveAggregatedRules(t *testing.T) {
+	t.Parallel()
+
+	objects := newTestObjects(t, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: admin
+aggregationRule:
+  clusterRoleSelectors:
+  - matchLabels:
+      aggregate-to-admin: "true"
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [list]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: admin-ext
+  labels:
+    aggregate-to-admin: "true"
+rules:
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [delete]
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: edit
+  labels:
+    aggregate-to-admin: "true"
+aggregationRule:
+  clusterRoleSelectors:
+  - matchLabels:
+      aggregate-to-edit: "true"
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: edit-ext
+  labels:
+    aggregate-to-edit: "true"
+rules:
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get]
+- apiGroups: [""]
+  resources: [configmaps]
+  verbs: [update]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: cycle-a
+  labels:
+    cycle: a
+aggregationRule:
+  clusterRoleSelectors:
+  - matchLabels:
+      cycle: b
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: cycle-b
+  labels:
+    cycle: b
+aggregationRule:
+  clusterRoleSelectors:
+  - matchLabels:
+      cycle: a
+`)
+	nodeMap := resolveTestGraph(t, objects, ResolveOptions{})
+
+	tests := []struct {
+		name string
+		// expected contains the aggregated rules of the ClusterRole, in the
+		// "<source>:<verbs>/<resources>" form
+		expected []string
+	}{
+		{
+			name: "admin",
+			expected: []string{
+				"admin-ext:delete/pods",
+				"admin-ext:get/pods",
+				"edit-ext:update/configmaps",
+			},
+		},
+		{
+			name:     "admin-ext",
+			expected: nil,
+		},
+		{
+			name: "edit",
+			expected: []string{
+				"edit-ext:get/pods",
+				"edit-ext:update/configmaps",
+			},
+		},
+		{
+			name:     "cycle-a",
+			expected: []string{},
+		},
+		{
+			name:     "cycle-b",
+			expected: []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			node, ok := nodeMap[testUID("ClusterRole", "", tt.name)]
+			if !ok {
+				t.Fatalf("expected ClusterRole \"%s\" got nil", tt.name)
+			}
+			var rules []string
+			if node.AggregatedRules != nil {
+				rules = []string{}
+			}
+			for _, r := range node.AggregatedRules {
+				rules = append(rules, r.Source+":"+strings.Join(r.Verbs, ",")+"/"+strings.Join(r.Resources, ","))
+			}
+			if !reflect.DeepEqual(rules, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, rules)
+			}
+		})
+	}
+}
+
+func TestResolvePolicyRuleRelationships(t *testing.T) {
+	t.Parallel()
+
+	objects := newTestObjects(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: default
+`, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: bar
+  namespace: other
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  namespace: default
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  namespace: other
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: secret-reader
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: secret-reader
+  namespace: default
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: secret-reader
+subjects:
+- kind: ServiceAccount
+  name: a
+  namespace: default
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: secret-reader
+  namespace: other
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: secret-reader
+subjects:
+- kind: ServiceAccount
+  name: b
+  namespace: other
+`, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: a
+  namespace: default
+`, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: b
+  namespace: other
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: cm-reader
+rules:
+- apiGroups: [""]
+  resources: [configmaps]
+  verbs: ["*"]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: cm-reader
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: cm-reader
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: unbound
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: admin
+rules:
+- apiGroups: [""]
+  resources: ["*"]
+  verbs: [get]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: admin
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: admin
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: r
+  namespace: default
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  resourceNames: [foo, bar]
+  verbs: [get]
+`, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: r
+  namespace: default
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: r
+`)
+
+	tests := []struct {
+		name     string
+		opts     ResolveOptions
+		expected []string
+	}{
+		{
+			name:     "no verbs",
+			opts:     ResolveOptions{},
+			expected: nil,
+		},
+		{
+			name: "verbs without wildcard rules",
+			opts: ResolveOptions{PolicyRuleVerbs: []string{"get"}},
+			expected: []string{
+				"ConfigMap/default/a -[ClusterRolePolicyRule]-> ClusterRoleBinding/cm-reader",
+				"ConfigMap/other/b -[ClusterRolePolicyRule]-> ClusterRoleBinding/cm-reader",
+				"Secret/default/foo -[ClusterRolePolicyRule]-> RoleBinding/default/secret-reader",
+				"Secret/default/foo -[RolePolicyRule]-> RoleBinding/default/r",
+				"Secret/other/bar -[ClusterRolePolicyRule]-> RoleBinding/other/secret-reader",
+			},
+		},
+		{
+			name: "verbs with wildcard rules",
+			opts: ResolveOptions{PolicyRuleVerbs: []string{"get"}, IncludeWildcardPolicyRules: true},
+			expected: []string{
+				"ConfigMap/default/a -[ClusterRolePolicyRule]-> ClusterRoleBinding/admin",
+				"ConfigMap/default/a -[ClusterRolePolicyRule]-> ClusterRoleBinding/cm-reader",
+				"ConfigMap/other/b -[ClusterRolePolicyRule]-> ClusterRoleBinding/admin",
+				"ConfigMap/other/b -[ClusterRolePolicyRule]-> ClusterRoleBinding/cm-reader",
+				"Secret/default/foo -[ClusterRolePolicyRule]-> ClusterRoleBinding/admin",
+				"Secret/default/foo -[ClusterRolePolicyRule]-> RoleBinding/default/secret-reader",
+				"Secret/default/foo -[RolePolicyRule]-> RoleBinding/default/r",
+				"Secret/other/bar -[ClusterRolePolicyRule]-> ClusterRoleBinding/admin",
+				"Secret/other/bar -[ClusterRolePolicyRule]-> RoleBinding/other/secret-reader",
+				"ServiceAccount/default/a -[ClusterRolePolicyRule]-> ClusterRoleBinding/admin",
+				"ServiceAccount/other/b -[ClusterRolePolicyRule]-> ClusterRoleBinding/admin",
+			},
+		},
+		{
+			name: "verbs only allowed by wildcard verbs",
+			opts: ResolveOptions{PolicyRuleVerbs: []string{"delete"}},
+			expected: []string{
+				"ConfigMap/default/a -[ClusterRolePolicyRule]-> ClusterRoleBinding/cm-reader",
+				"ConfigMap/other/b -[ClusterRolePolicyRule]-> ClusterRoleBinding/cm-reader",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var edges []string
+			for _, e := range getTestEdges(resolveTestGraph(t, objects, tt.opts)) {
+				if strings.Contains(e, "PolicyRule]") {
+					edges = append(edges, e)
+				}
+			}
+			if !reflect.DeepEqual(edges, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, edges)
+			}
+		})
+	}
+
+	// Subjects of a RoleBinding must not be dependents of objects in other
+	// namespaces, even if other RoleBindings reference the same ClusterRole
+	t.Run("subjects of role bindings", func(t *testing.T) {
+		t.Parallel()
+
+		depMap, err := ResolveDependents(newTestRESTMapper(objects), objects, []types.UID{testUID("Secret", "other", "bar")}, ResolveOptions{PolicyRuleVerbs: []string{"get"}})
+		if err != nil {
+			t.Fatalf("failed to resolve dependents: %v", err)
+		}
+		var deps []string
+		for uid, n := range depMap {
+			if uid == n.UID {
+				deps = append(deps, testNodeName(n))
+			}
+		}
+		sort.Strings(deps)
+		expected := []string{
+			"RoleBinding/other/secret-reader",
+			"Secret/other/bar",
+			"ServiceAccount/other/b",
+		}
+		if !reflect.DeepEqual(deps, expected) {
+			t.Fatalf("expected %q got %q", expected, deps)
+		}
+	})
+}
+
+func TestGetNetworkPolicyRelationships(t *testing.T) {
+	t.Parallel()
+
+	objects := newTestObjects(t, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: default
+  labels:
+    team: a
+`, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: other
+  labels:
+    team: b
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: default
+  labels:
+    app: web
+spec:
+  containers:
+  - name: app
+    image: nginx
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: client
+  namespace: default
+  labels:
+    app: client
+spec:
+  containers:
+  - name: app
+    image: nginx
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: client
+  namespace: other
+  labels:
+    app: client
+spec:
+  containers:
+  - name: app
+    image: nginx
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db
+  namespace: other
+  labels:
+    app: db
+spec:
+  containers:
+  - name: app
+    image: nginx
+`, `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: web
+  namespace: default
+spec:
+  podSelector:
+    matchLabels:
+      app: web
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          app: client
+    - ipBlock:
+        cidr: 10.0.0.0/8
+  egress:
+  - to:
+    - namespaceSelector:
+        matchLabels:
+          team: b
+      podSelector:
+        matchLabels:
+          app: db
+`)
+	nodeMap := resolveTestGraph(t, objects, ResolveOptions{})
+
+	var edges []string
+	for _, e := range getTestEdges(nodeMap) {
+		if strings.Contains(e, "NetworkPolicy") {
+			edges = append(edges, e)
+		}
+	}
+	expected := []string{
+		"NetworkPolicy/default/web -[NetworkPolicyEgressPeer]-> Pod/other/db",
+		"NetworkPolicy/default/web -[NetworkPolicyIngressPeer]-> Pod/default/client",
+		"Pod/default/web -[NetworkPolicy]-> NetworkPolicy/default/web",
+	}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Fatalf("expected %q got %q", expected, edges)
+	}
+
+	// Peers must be reachable from the targeted pods through the NetworkPolicy
+	var paths []string
+	for _, p := range FindShortestPaths(nodeMap, testUID("Pod", "default", "web"), testUID("Pod", "other", "db"), 0) {
+		paths = append(paths, testPathString(p))
+	}
+	expected = []string{
+		"Pod/default/web -[NetworkPolicy]-> NetworkPolicy/default/web -[NetworkPolicyEgressPeer]-> Pod/other/db",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected %q got %q", expected, paths)
+	}
+}
@@ -0,0 +1,169 @@
+package graph
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PathEdge represents a step in a relationship path between two objects.
+type PathEdge struct {
+	From *Node
+	To   *Node
+	// IsDependency is true if To is a dependency of From, otherwise To is a
+	// dependent of From.
+	IsDependency  bool
+	Relationships RelationshipSet
+}
+
+// Path is a sequence of relationships that connects two objects.
+type Path []PathEdge
+
+// pathPredecessor represents the previous node of a node in a shortest path.
+type pathPredecessor struct {
+	uid          types.UID
+	isDependency bool
+}
+
+// FindShortestPaths returns all of the shortest relationship paths between the
+// objects with the provided UIDs. Relationships are traversed in both
+// directions, so each step of a path can either be a dependency or a dependent
+// of its previous object. At most limit paths are returned, unless limit is 0.
+func FindShortestPaths(nodeMap NodeMap, fromUID, toUID types.UID, limit uint) []Path {
+	from, to := nodeMap[fromUID], nodeMap[toUID]
+	if from == nil || to == nil {
+		return nil
+	}
+	if fromUID == toUID {
+		return []Path{{}}
+	}
+
+	// Find the distance of each object from the source object using a
+	// breadth-first search, while keeping track of all predecessors of each
+	// object which are on one of its shortest paths
+	distByUID := map[types.UID]int{fromUID: 0}
+	predsByUID := map[types.UID][]pathPredecessor{}
+	queue := []types.UID{fromUID}
+	for len(queue) > 0 {
+		uid := queue[0]
+		queue = queue[1:]
+		if uid == toUID {
+			break
+		}
+		node, dist := nodeMap[uid], distByUID[uid]
+		visitFn := func(depUID types.UID, isDependency bool) {
+			if _, ok := nodeMap[depUID]; !ok {
+				return
+			}
+			d, ok := distByUID[depUID]
+			if !ok {
+				distByUID[depUID] = dist + 1
+				queue = append(queue, depUID)
+			} else if d != dist+1 {
+				return
+			}
+			predsByUID[depUID] = append(predsByUID[depUID], pathPredecessor{uid: uid, isDependency: isDependency})
+		}
+		for depUID := range node.Dependencies {
+			visitFn(depUID, true)
+		}
+		for depUID := range node.Dependents {
+			visitFn(depUID, false)
+		}
+	}
+	if _, ok := distByUID[toUID]; !ok {
+		return nil
+	}
+
+	// Walk backwards from the target object to enumerate all shortest paths
+	var paths []Path
+	var walkFn func(uid types.UID, suffix Path)
+	walkFn = func(uid types.UID, suffix Path) {
+		// Stop enumerating paths once the limit is reached, as the number of
+		// shortest paths can grow exponentially with their length
+		if limit > 0 && uint(len(paths)) >= limit {
+			return
+		}
+		if uid == fromUID {
+			path := make(Path, len(suffix))
+			copy(path, suffix)
+			paths = append(paths, path)
+			return
+		}
+		node, preds := nodeMap[uid], predsByUID[uid]
+		sort.SliceStable(preds, func(i, j int) bool {
+			a, b := nodeMap[preds[i].uid], nodeMap[preds[j].uid]
+			if a != b {
+				return NodeList{a, b}.Less(0, 1)
+			}
+			return preds[i].isDependency && !preds[j].isDependency
+		})
+		for _, pred := range preds {
+			prev := nodeMap[pred.uid]
+			rset := prev.Dependents[uid]
+			if pred.isDependency {
+				rset = prev.Dependencies[uid]
+			}
+			edge := PathEdge{From: prev, To: node, IsDependency: pred.isDependency, Relationships: rset}
+			walkFn(pred.uid, append(Path{edge}, suffix...))
+		}
+	}
+	walkFn(toUID, Path{})
+
+	return paths
+}
+
+// FindOwnerPaths returns the relationship paths from each of the top-level
+// owners of the object with the provided UID down to the object itself, by
+// walking up its owner references until reaching objects without any owner.
+// Objects without any owner have no owner paths.
+func FindOwnerPaths(nodeMap NodeMap, uid types.UID) []Path {
+	if _, ok := nodeMap[uid]; !ok {
+		return nil
+	}
+
+	var paths []Path
+	var walkFn func(uid types.UID, suffix Path, visited map[types.UID]struct{})
+	walkFn = func(uid types.UID, suffix Path, visited map[types.UID]struct{}) {
+		node := nodeMap[uid]
+		var owners NodeList
+		for depUID, rset := range node.Dependencies {
+			owner, ok := nodeMap[depUID]
+			if !ok {
+				continue
+			}
+			if _, ok := rset[RelationshipOwnerRef]; !ok {
+				continue
+			}
+			// Guard against possible cycles
+			if _, ok := visited[depUID]; ok {
+				continue
+			}
+			owners = append(owners, owner)
+		}
+		if len(owners) == 0 {
+			if len(suffix) > 0 {
+				path := make(Path, len(suffix))
+				copy(path, suffix)
+				paths = append(paths, path)
+			}
+			return
+		}
+		sort.Sort(owners)
+		visited[uid] = struct{}{}
+		for _, owner := range owners {
+			rset := RelationshipSet{}
+			for r := range owner.Dependents[uid] {
+				if r == RelationshipControllerRef || r == RelationshipOwnerRef {
+					rset[r] = struct{}{}
+				}
+			}
+			edge := PathEdge{From: owner, To: node, Relationships: rset}
+			walkFn(owner.UID, append(Path{edge}, suffix...), visited)
+		}
+		delete(visited, uid)
+	}
+	walkFn(uid, Path{}, map[types.UID]struct{}{})
+
+	return paths
+}
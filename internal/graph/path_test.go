@@ -0,0 +1,284 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// testPathString returns the provided path in the same form as it's printed,
+// where an arrow points from an object towards its dependent.
+func testPathString(p Path) string {
+	if len(p) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(testNodeName(p[0].From))
+	for _, e := range p {
+		rels := strings.Join(e.Relationships.List(), ",")
+		if e.IsDependency {
+			sb.WriteString(" <-[" + rels + "]- ")
+		} else {
+			sb.WriteString(" -[" + rels + "]-> ")
+		}
+		sb.WriteString(testNodeName(e.To))
+	}
+	return sb.String()
+}
+
+func TestFindShortestPaths(t *testing.T) {
+	t.Parallel()
+
+	objects := newTestObjects(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: default
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: a
+  namespace: default
+  labels:
+    app: bar
+spec:
+  containers:
+  - name: app
+    image: nginx
+  volumes:
+  - name: foo
+    secret:
+      secretName: foo
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: b
+  namespace: default
+  labels:
+    app: bar
+spec:
+  containers:
+  - name: app
+    image: nginx
+  volumes:
+  - name: foo
+    secret:
+      secretName: foo
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: bar
+  namespace: default
+spec:
+  selector:
+    app: bar
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: baz
+  namespace: default
+`)
+	nodeMap := resolveTestGraph(t, objects, ResolveOptions{})
+
+	secretUID := testUID("Secret", "default", "foo")
+	serviceUID := testUID("Service", "default", "bar")
+	configMapUID := testUID("ConfigMap", "default", "baz")
+	tests := []struct {
+		name     string
+		from     types.UID
+		to       types.UID
+		limit    uint
+		expected []string
+	}{
+		{
+			name:  "multiple shortest paths",
+			from:  secretUID,
+			to:    serviceUID,
+			limit: 0,
+			expected: []string{
+				"Secret/default/foo -[PodVolume]-> Pod/default/a -[Service]-> Service/default/bar",
+				"Secret/default/foo -[PodVolume]-> Pod/default/b -[Service]-> Service/default/bar",
+			},
+		},
+		{
+			name:  "multiple shortest paths in reverse",
+			from:  serviceUID,
+			to:    secretUID,
+			limit: 0,
+			expected: []string{
+				"Service/default/bar <-[Service]- Pod/default/a <-[PodVolume]- Secret/default/foo",
+				"Service/default/bar <-[Service]- Pod/default/b <-[PodVolume]- Secret/default/foo",
+			},
+		},
+		{
+			name:  "multiple shortest paths with limit",
+			from:  secretUID,
+			to:    serviceUID,
+			limit: 1,
+			expected: []string{
+				"Secret/default/foo -[PodVolume]-> Pod/default/a -[Service]-> Service/default/bar",
+			},
+		},
+		{
+			name:     "same object",
+			from:     secretUID,
+			to:       secretUID,
+			limit:    0,
+			expected: []string{""},
+		},
+		{
+			name:     "unrelated objects",
+			from:     secretUID,
+			to:       configMapUID,
+			limit:    0,
+			expected: nil,
+		},
+		{
+			name:     "unknown object",
+			from:     secretUID,
+			to:       "unknown",
+			limit:    0,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var paths []string
+			for _, p := range FindShortestPaths(nodeMap, tt.from, tt.to, tt.limit) {
+				paths = append(paths, testPathString(p))
+			}
+			if !reflect.DeepEqual(paths, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, paths)
+			}
+		})
+	}
+}
+
+func TestFindOwnerPaths(t *testing.T) {
+	t.Parallel()
+
+	objects := newTestObjects(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+`, `
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: foo-1
+  namespace: default
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: foo
+    uid: Deployment/default/foo
+    controller: true
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bar
+  namespace: default
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: foo-1-x
+  namespace: default
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: ReplicaSet
+    name: foo-1
+    uid: ReplicaSet/default/foo-1
+    controller: true
+  - apiVersion: v1
+    kind: ConfigMap
+    name: bar
+    uid: ConfigMap/default/bar
+spec:
+  containers:
+  - name: app
+    image: nginx
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cycle-a
+  namespace: default
+  ownerReferences:
+  - apiVersion: v1
+    kind: ConfigMap
+    name: cycle-b
+    uid: ConfigMap/default/cycle-b
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cycle-b
+  namespace: default
+  ownerReferences:
+  - apiVersion: v1
+    kind: ConfigMap
+    name: cycle-a
+    uid: ConfigMap/default/cycle-a
+`)
+	nodeMap := resolveTestGraph(t, objects, ResolveOptions{})
+
+	tests := []struct {
+		name     string
+		uid      types.UID
+		expected []string
+	}{
+		{
+			name: "multiple owners",
+			uid:  testUID("Pod", "default", "foo-1-x"),
+			expected: []string{
+				"ConfigMap/default/bar -[OwnerReference]-> Pod/default/foo-1-x",
+				"Deployment/default/foo -[ControllerReference,OwnerReference]-> ReplicaSet/default/foo-1 -[ControllerReference,OwnerReference]-> Pod/default/foo-1-x",
+			},
+		},
+		{
+			name:     "top-level owner",
+			uid:      testUID("Deployment", "default", "foo"),
+			expected: nil,
+		},
+		{
+			name: "owner cycle",
+			uid:  testUID("ConfigMap", "default", "cycle-a"),
+			expected: []string{
+				"ConfigMap/default/cycle-b -[OwnerReference]-> ConfigMap/default/cycle-a",
+			},
+		},
+		{
+			name:     "unknown object",
+			uid:      "unknown",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var paths []string
+			for _, p := range FindOwnerPaths(nodeMap, tt.uid) {
+				paths = append(paths, testPathString(p))
+			}
+			if !reflect.DeepEqual(paths, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"sync"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	nodev1 "k8s.io/api/node/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	storagev1beta1 "k8s.io/api/storage/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+)
+
+// RelationshipResolver returns a map of relationships that the provided object
+// has with other objects.
+type RelationshipResolver func(n *Node) (*RelationshipMap, error)
+
+// PostResolveHook updates the relationships of the objects in the provided node
+// map once the relationships of every object are resolved, for relationships
+// that depend on other objects than the ones they're between.
+type PostResolveHook func(nodeMap map[types.UID]*Node)
+
+// resolverRegistry contains the relationship resolvers & relationship rules of
+// each resource type, mapped by their GroupKind, along with the post-resolve
+// hooks run in the order they're registered.
+type resolverRegistry struct {
+	mu        sync.RWMutex
+	resolvers map[schema.GroupKind]RelationshipResolver
+	rules     map[schema.GroupKind][]*compiledRelationshipRule
+	hooks     []PostResolveHook
+}
+
+//nolint:gochecknoglobals
+var registry = newResolverRegistry()
+
+// newResolverRegistry returns a resolver registry with all of the built-in
+// relationship resolvers registered.
+func newResolverRegistry() *resolverRegistry {
+	return &resolverRegistry{
+		resolvers: map[schema.GroupKind]RelationshipResolver{
+			{Group: admissionregistrationv1.GroupName, Kind: "MutatingWebhookConfiguration"}:   getMutatingWebhookConfigurationRelationships,
+			{Group: admissionregistrationv1.GroupName, Kind: "ValidatingWebhookConfiguration"}: getValidatingWebhookConfigurationRelationships,
+			{Group: apiextensionsv1.GroupName, Kind: "CustomResourceDefinition"}:               getCustomResourceDefinitionRelationships,
+			{Group: apiregistrationv1.GroupName, Kind: "APIService"}:                           getAPIServiceRelationships,
+			{Group: appsv1.GroupName, Kind: "DaemonSet"}:                                       getWorkloadRelationships,
+			{Group: appsv1.GroupName, Kind: "Deployment"}:                                      getWorkloadRelationships,
+			{Group: appsv1.GroupName, Kind: "StatefulSet"}:                                     getStatefulSetRelationships,
+			{Group: autoscalingv1.GroupName, Kind: "HorizontalPodAutoscaler"}:                  getHorizontalPodAutoscalerRelationships,
+			{Group: VerticalPodAutoscalerGroupName, Kind: "VerticalPodAutoscaler"}:             getVerticalPodAutoscalerRelationships,
+			{Group: batchv1.GroupName, Kind: "CronJob"}:                                        getWorkloadRelationships,
+			{Group: batchv1.GroupName, Kind: "Job"}:                                            getWorkloadRelationships,
+			{Group: corev1.GroupName, Kind: "Endpoints"}:                                       getEndpointsRelationships,
+			{Group: corev1.GroupName, Kind: "Event"}:                                           getEventRelationships,
+			{Group: corev1.GroupName, Kind: "PersistentVolume"}:                                getPersistentVolumeRelationships,
+			{Group: corev1.GroupName, Kind: "PersistentVolumeClaim"}:                           getPersistentVolumeClaimRelationships,
+			{Group: corev1.GroupName, Kind: "Pod"}:                                             getPodRelationships,
+			{Group: corev1.GroupName, Kind: "Service"}:                                         getServiceRelationships,
+			{Group: corev1.GroupName, Kind: "ServiceAccount"}:                                  getServiceAccountRelationships,
+			{Group: discoveryv1.GroupName, Kind: "EndpointSlice"}:                              getEndpointSliceRelationships,
+			{Group: eventsv1.GroupName, Kind: "Event"}:                                         getEventRelationships,
+			{Group: extensionsv1beta1.GroupName, Kind: "Ingress"}:                              getIngressRelationships,
+			{Group: GatewayAPIGroupName, Kind: "Gateway"}:                                      getGatewayRelationships,
+			{Group: GatewayAPIGroupName, Kind: "GatewayClass"}:                                 getGatewayClassRelationships,
+			{Group: GatewayAPIGroupName, Kind: "GRPCRoute"}:                                    getRouteRelationships,
+			{Group: GatewayAPIGroupName, Kind: "HTTPRoute"}:                                    getRouteRelationships,
+			{Group: GatewayAPIGroupName, Kind: "ReferenceGrant"}:                               getReferenceGrantRelationships,
+			{Group: GatewayAPIGroupName, Kind: "TLSRoute"}:                                     getRouteRelationships,
+			{Group: networkingv1.GroupName, Kind: "Ingress"}:                                   getIngressRelationships,
+			{Group: networkingv1.GroupName, Kind: "IngressClass"}:                              getIngressClassRelationships,
+			{Group: networkingv1.GroupName, Kind: "NetworkPolicy"}:                             getNetworkPolicyRelationships,
+			{Group: nodev1.GroupName, Kind: "RuntimeClass"}:                                    getRuntimeClassRelationships,
+			{Group: policyv1.GroupName, Kind: "PodDisruptionBudget"}:                           getPodDisruptionBudgetRelationships,
+			{Group: policyv1beta1.GroupName, Kind: "PodSecurityPolicy"}:                        getPodSecurityPolicyRelationships,
+			{Group: rbacv1.GroupName, Kind: "ClusterRole"}:                                     getClusterRoleRelationships,
+			{Group: rbacv1.GroupName, Kind: "ClusterRoleBinding"}:                              getClusterRoleBindingRelationships,
+			{Group: rbacv1.GroupName, Kind: "Role"}:                                            getRoleRelationships,
+			{Group: rbacv1.GroupName, Kind: "RoleBinding"}:                                     getRoleBindingRelationships,
+			{Group: storagev1.GroupName, Kind: "CSINode"}:                                      getCSINodeRelationships,
+			{Group: storagev1.GroupName, Kind: "StorageClass"}:                                 getStorageClassRelationships,
+			{Group: storagev1.GroupName, Kind: "VolumeAttachment"}:                             getVolumeAttachmentRelationships,
+			{Group: storagev1beta1.GroupName, Kind: "CSIStorageCapacity"}:                      getCSIStorageCapacityRelationships,
+			{Group: VolumeSnapshotGroupName, Kind: "VolumeSnapshot"}:                           getVolumeSnapshotRelationships,
+			{Group: VolumeSnapshotGroupName, Kind: "VolumeSnapshotClass"}:                      getVolumeSnapshotClassRelationships,
+			{Group: VolumeSnapshotGroupName, Kind: "VolumeSnapshotContent"}:                    getVolumeSnapshotContentRelationships,
+		},
+		rules: map[schema.GroupKind][]*compiledRelationshipRule{},
+		hooks: []PostResolveHook{
+			pruneDisallowedRouteReferences,
+		},
+	}
+}
+
+// RegisterResolver registers the relationship resolver for objects of the
+// provided GroupKind, replacing any resolver previously registered for it.
+func RegisterResolver(gk schema.GroupKind, r RelationshipResolver) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.resolvers[gk] = r
+}
+
+// GetResolver returns the relationship resolver registered for objects of the
+// provided GroupKind, combined with the relationship rules registered for it.
+func GetResolver(gk schema.GroupKind) (RelationshipResolver, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	resolve, hasResolver := registry.resolvers[gk]
+	rules, hasRules := registry.rules[gk]
+	if !hasRules {
+		return resolve, hasResolver
+	}
+	return func(n *Node) (*RelationshipMap, error) {
+		var result *RelationshipMap
+		if hasResolver {
+			rmap, err := resolve(n)
+			if err != nil {
+				return nil, err
+			}
+			result = rmap
+		} else {
+			rmap := NewRelationshipMap()
+			result = &rmap
+		}
+		for _, r := range rules {
+			if err := r.apply(n, result); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}, true
+}
+
+// registerRules registers the provided relationship rules, replacing all rules
+// previously registered for the same GroupKinds.
+func registerRules(rulesByGK map[schema.GroupKind][]*compiledRelationshipRule) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	for gk, rules := range rulesByGK {
+		registry.rules[gk] = rules
+	}
+}
+
+// RegisterPostResolveHook registers the provided post-resolve hook, which is run
+// after the hooks previously registered.
+func RegisterPostResolveHook(h PostResolveHook) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.hooks = append(registry.hooks, h)
+}
+
+// getPostResolveHooks returns the registered post-resolve hooks, in the order
+// they're registered.
+func getPostResolveHooks() []PostResolveHook {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	hooks := make([]PostResolveHook, len(registry.hooks))
+	copy(hooks, registry.hooks)
+	return hooks
+}
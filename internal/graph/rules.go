@@ -0,0 +1,342 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// RelationshipRuleMatchType determines how the target objects of a
+// relationship rule are matched.
+type RelationshipRuleMatchType string
+
+const (
+	// RelationshipRuleMatchByName matches target objects by their name.
+	RelationshipRuleMatchByName RelationshipRuleMatchType = "Name"
+	// RelationshipRuleMatchByLabelSelector matches target objects by a label
+	// selector, either in the form of a map of labels or a LabelSelector.
+	RelationshipRuleMatchByLabelSelector RelationshipRuleMatchType = "LabelSelector"
+	// RelationshipRuleMatchByUID matches target objects by their UID.
+	RelationshipRuleMatchByUID RelationshipRuleMatchType = "UID"
+)
+
+// RelationshipRuleNamespace determines the namespace of the target objects of
+// a relationship rule.
+type RelationshipRuleNamespace string
+
+const (
+	// RelationshipRuleNamespaceSame matches target objects in the same namespace
+	// as the source object.
+	RelationshipRuleNamespaceSame RelationshipRuleNamespace = "Same"
+	// RelationshipRuleNamespaceCluster matches cluster-scoped target objects.
+	RelationshipRuleNamespaceCluster RelationshipRuleNamespace = "Cluster"
+	// RelationshipRuleNamespaceJSONPath matches target objects in the namespace
+	// found at the target's namespace JSONPath, falling back to the namespace of
+	// the source object if the field is empty.
+	RelationshipRuleNamespaceJSONPath RelationshipRuleNamespace = "JSONPath"
+)
+
+// RelationshipRules contains a list of relationship rules.
+type RelationshipRules struct {
+	Rules []RelationshipRule `json:"rules"`
+}
+
+// RelationshipRule declares a relationship between objects of a resource type
+// & the objects referenced by one of their fields.
+type RelationshipRule struct {
+	// Group & Kind of the objects that the rule applies to.
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+	// JSONPath of the field(s) referencing the target objects, either in the
+	// "{.spec.field}" form or the relaxed "spec.field" form.
+	JSONPath string `json:"jsonPath"`
+	// Target describes the objects referenced by the field(s).
+	Target RelationshipRuleTarget `json:"target"`
+	// Relationship is the name of the relationship type.
+	Relationship Relationship `json:"relationship"`
+	// Dependent is true if the target objects are dependents of the objects
+	// that the rule applies to, otherwise they're dependencies.
+	Dependent bool `json:"dependent,omitempty"`
+}
+
+// RelationshipRuleTarget describes the objects referenced by a relationship
+// rule.
+type RelationshipRuleTarget struct {
+	Group             string                    `json:"group"`
+	Kind              string                    `json:"kind"`
+	MatchBy           RelationshipRuleMatchType `json:"matchBy,omitempty"`
+	Namespace         RelationshipRuleNamespace `json:"namespace,omitempty"`
+	NamespaceJSONPath string                    `json:"namespaceJSONPath,omitempty"`
+}
+
+// DefaultRelationshipRulesFile returns the path of the relationship rules file
+// that is loaded when no file is explicitly provided.
+func DefaultRelationshipRulesFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".kube-lineage", "rules.yaml")
+}
+
+// LoadRelationshipRules reads the relationship rules from the provided file &
+// registers them. If no file is provided, rules are read from the default
+// relationship rules file if it exists.
+func LoadRelationshipRules(path string) error {
+	isDefault := len(path) == 0
+	if isDefault {
+		path = DefaultRelationshipRulesFile()
+		if len(path) == 0 {
+			return nil
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if isDefault && os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var rules RelationshipRules
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return fmt.Errorf("failed to parse relationship rules file \"%s\": %w", path, err)
+	}
+	klog.V(4).Infof("Loaded %d relationship rules from \"%s\"", len(rules.Rules), path)
+	return RegisterRelationshipRules(rules.Rules)
+}
+
+// RegisterRelationshipRules registers the provided relationship rules.
+// Relationships from the rules are added on top of the relationships from the
+// resolver registered for the same resource type. Rules previously registered
+// for the same resource type are replaced, so registering the same rules more
+// than once doesn't apply them more than once.
+func RegisterRelationshipRules(rules []RelationshipRule) error {
+	rulesByGK := map[schema.GroupKind][]*compiledRelationshipRule{}
+	for ix := range rules {
+		r, err := compileRelationshipRule(rules[ix])
+		if err != nil {
+			return fmt.Errorf("invalid relationship rule #%d: %w", ix+1, err)
+		}
+		gk := schema.GroupKind{Group: r.Group, Kind: r.Kind}
+		rulesByGK[gk] = append(rulesByGK[gk], r)
+	}
+	registerRules(rulesByGK)
+	return nil
+}
+
+// compiledRelationshipRule is a relationship rule with its JSONPaths parsed.
+type compiledRelationshipRule struct {
+	RelationshipRule
+	jsonPath          *jsonpath.JSONPath
+	namespaceJSONPath *jsonpath.JSONPath
+}
+
+// compileRelationshipRule validates the provided relationship rule, sets its
+// default values & parses its JSONPaths.
+//
+//nolint:gocyclo
+func compileRelationshipRule(r RelationshipRule) (*compiledRelationshipRule, error) {
+	if len(r.Kind) == 0 {
+		return nil, fmt.Errorf("kind must be specified")
+	}
+	if len(r.JSONPath) == 0 {
+		return nil, fmt.Errorf("jsonPath must be specified")
+	}
+	if len(r.Target.Kind) == 0 {
+		return nil, fmt.Errorf("target kind must be specified")
+	}
+	if len(r.Relationship) == 0 {
+		return nil, fmt.Errorf("relationship must be specified")
+	}
+	if len(r.Target.MatchBy) == 0 {
+		r.Target.MatchBy = RelationshipRuleMatchByName
+	}
+	if len(r.Target.Namespace) == 0 {
+		r.Target.Namespace = RelationshipRuleNamespaceSame
+	}
+	switch r.Target.MatchBy {
+	case RelationshipRuleMatchByName, RelationshipRuleMatchByLabelSelector, RelationshipRuleMatchByUID:
+	default:
+		return nil, fmt.Errorf("unsupported target matchBy \"%s\"", r.Target.MatchBy)
+	}
+	switch r.Target.Namespace {
+	case RelationshipRuleNamespaceSame, RelationshipRuleNamespaceCluster:
+	case RelationshipRuleNamespaceJSONPath:
+		if len(r.Target.NamespaceJSONPath) == 0 {
+			return nil, fmt.Errorf("target namespaceJSONPath must be specified when target namespace is \"%s\"", r.Target.Namespace)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported target namespace \"%s\"", r.Target.Namespace)
+	}
+
+	result := compiledRelationshipRule{RelationshipRule: r}
+	jsonPath, err := relaxedJSONPathExpression(r.JSONPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jsonPath: %w", err)
+	}
+	result.jsonPath = jsonpath.New("jsonPath").AllowMissingKeys(true)
+	if err := result.jsonPath.Parse(jsonPath); err != nil {
+		return nil, fmt.Errorf("failed to parse jsonPath: %w", err)
+	}
+	if len(r.Target.NamespaceJSONPath) > 0 {
+		namespaceJSONPath, err := relaxedJSONPathExpression(r.Target.NamespaceJSONPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse target namespaceJSONPath: %w", err)
+		}
+		result.namespaceJSONPath = jsonpath.New("namespaceJSONPath").AllowMissingKeys(true)
+		if err := result.namespaceJSONPath.Parse(namespaceJSONPath); err != nil {
+			return nil, fmt.Errorf("failed to parse target namespaceJSONPath: %w", err)
+		}
+	}
+
+	return &result, nil
+}
+
+//nolint:gochecknoglobals
+var relaxedJSONPathRegexp = regexp.MustCompile(`^\{\.?([^{}]+)\}$|^\.?([^{}]+)$`)
+
+// relaxedJSONPathExpression returns the provided JSONPath in the "{.field}"
+// form, accepting "field", ".field", "{field}" & "{.field}" the same way
+// kubectl does for custom columns. Paths without braces would otherwise be
+// parsed as literal text instead of a field reference.
+func relaxedJSONPathExpression(path string) (string, error) {
+	submatches := relaxedJSONPathRegexp.FindStringSubmatch(path)
+	if submatches == nil {
+		return "", fmt.Errorf("unexpected path \"%s\", expected a 'name1.name2' or '.name1.name2' or '{name1.name2}' or '{.name1.name2}'", path)
+	}
+	fieldSpec := submatches[1]
+	if len(fieldSpec) == 0 {
+		fieldSpec = submatches[2]
+	}
+	return fmt.Sprintf("{.%s}", fieldSpec), nil
+}
+
+// findValues returns all values found at the provided JSONPath of the object.
+func findValues(n *Node, jp *jsonpath.JSONPath) ([]interface{}, error) {
+	results, err := jp.FindResults(n.UnstructuredContent())
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for _, result := range results {
+		for _, v := range result {
+			if v.IsValid() && v.CanInterface() {
+				values = append(values, v.Interface())
+			}
+		}
+	}
+	return values, nil
+}
+
+// getNamespace returns the namespace of the target objects referenced by the
+// provided object.
+func (r *compiledRelationshipRule) getNamespace(n *Node) (string, error) {
+	switch r.Target.Namespace {
+	case RelationshipRuleNamespaceCluster:
+		return "", nil
+	case RelationshipRuleNamespaceJSONPath:
+		values, err := findValues(n, r.namespaceJSONPath)
+		if err != nil {
+			return "", err
+		}
+		for _, v := range values {
+			if ns, ok := v.(string); ok && len(ns) > 0 {
+				return ns, nil
+			}
+		}
+	}
+	return n.Namespace, nil
+}
+
+// apply adds the relationships that the provided object has with the target
+// objects referenced by the rule into the provided relationship map.
+func (r *compiledRelationshipRule) apply(n *Node, rmap *RelationshipMap) error {
+	values, err := findValues(n, r.jsonPath)
+	if err != nil {
+		return err
+	}
+	ns, err := r.getNamespace(n)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range values {
+		switch r.Target.MatchBy {
+		case RelationshipRuleMatchByName:
+			name, ok := v.(string)
+			if !ok || len(name) == 0 {
+				continue
+			}
+			ref := ObjectReference{Group: r.Target.Group, Kind: r.Target.Kind, Namespace: ns, Name: name}
+			if r.Dependent {
+				rmap.AddDependentByKey(ref.Key(), r.Relationship)
+			} else {
+				rmap.AddDependencyByKey(ref.Key(), r.Relationship)
+			}
+		case RelationshipRuleMatchByLabelSelector:
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			selector, err := toLabelSelector(m)
+			if err != nil {
+				return err
+			}
+			if selector.Empty() {
+				continue
+			}
+			ols := ObjectLabelSelector{Group: r.Target.Group, Kind: r.Target.Kind, Namespace: ns, Selector: selector}
+			if r.Dependent {
+				rmap.AddDependentByLabelSelector(ols, r.Relationship)
+			} else {
+				rmap.AddDependencyByLabelSelector(ols, r.Relationship)
+			}
+		case RelationshipRuleMatchByUID:
+			uid, ok := v.(string)
+			if !ok || len(uid) == 0 {
+				continue
+			}
+			if r.Dependent {
+				rmap.AddDependentByUID(types.UID(uid), r.Relationship)
+			} else {
+				rmap.AddDependencyByUID(types.UID(uid), r.Relationship)
+			}
+		}
+	}
+
+	return nil
+}
+
+// toLabelSelector converts the provided field value into a label selector. The
+// value can either be a LabelSelector (ie. containing "matchLabels" and/or
+// "matchExpressions") or a map of labels.
+func toLabelSelector(m map[string]interface{}) (labels.Selector, error) {
+	_, hasMatchLabels := m["matchLabels"]
+	_, hasMatchExpressions := m["matchExpressions"]
+	if hasMatchLabels || hasMatchExpressions {
+		var ls metav1.LabelSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &ls); err != nil {
+			return nil, err
+		}
+		return metav1.LabelSelectorAsSelector(&ls)
+	}
+	set := labels.Set{}
+	for k, val := range m {
+		s, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("label selector value of \"%s\" must be a string", k)
+		}
+		set[k] = s
+	}
+	return labels.ValidatedSelectorFromSet(set)
+}
@@ -0,0 +1,355 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCompileRelationshipRule(t *testing.T) {
+	t.Parallel()
+
+	validRule := func(fn func(r *RelationshipRule)) RelationshipRule {
+		r := RelationshipRule{
+			Group:        "example.com",
+			Kind:         "Widget",
+			JSONPath:     "{.spec.secretName}",
+			Target:       RelationshipRuleTarget{Kind: "Secret"},
+			Relationship: "WidgetSecret",
+		}
+		if fn != nil {
+			fn(&r)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name              string
+		rule              RelationshipRule
+		expectedErr       bool
+		expectedMatchBy   RelationshipRuleMatchType
+		expectedNamespace RelationshipRuleNamespace
+	}{
+		{
+			name:              "default target matchBy & namespace",
+			rule:              validRule(nil),
+			expectedMatchBy:   RelationshipRuleMatchByName,
+			expectedNamespace: RelationshipRuleNamespaceSame,
+		},
+		{
+			name: "target namespace from JSONPath",
+			rule: validRule(func(r *RelationshipRule) {
+				r.Target.MatchBy = RelationshipRuleMatchByLabelSelector
+				r.Target.Namespace = RelationshipRuleNamespaceJSONPath
+				r.Target.NamespaceJSONPath = "{.spec.secretNamespace}"
+			}),
+			expectedMatchBy:   RelationshipRuleMatchByLabelSelector,
+			expectedNamespace: RelationshipRuleNamespaceJSONPath,
+		},
+		{
+			name:              "relaxed jsonPath",
+			rule:              validRule(func(r *RelationshipRule) { r.JSONPath = "spec.secretName" }),
+			expectedMatchBy:   RelationshipRuleMatchByName,
+			expectedNamespace: RelationshipRuleNamespaceSame,
+		},
+		{
+			name:        "missing kind",
+			rule:        validRule(func(r *RelationshipRule) { r.Kind = "" }),
+			expectedErr: true,
+		},
+		{
+			name:        "missing jsonPath",
+			rule:        validRule(func(r *RelationshipRule) { r.JSONPath = "" }),
+			expectedErr: true,
+		},
+		{
+			name:        "missing target kind",
+			rule:        validRule(func(r *RelationshipRule) { r.Target.Kind = "" }),
+			expectedErr: true,
+		},
+		{
+			name:        "missing relationship",
+			rule:        validRule(func(r *RelationshipRule) { r.Relationship = "" }),
+			expectedErr: true,
+		},
+		{
+			name:        "unsupported target matchBy",
+			rule:        validRule(func(r *RelationshipRule) { r.Target.MatchBy = "Annotation" }),
+			expectedErr: true,
+		},
+		{
+			name:        "unsupported target namespace",
+			rule:        validRule(func(r *RelationshipRule) { r.Target.Namespace = "Other" }),
+			expectedErr: true,
+		},
+		{
+			name:        "missing target namespaceJSONPath",
+			rule:        validRule(func(r *RelationshipRule) { r.Target.Namespace = RelationshipRuleNamespaceJSONPath }),
+			expectedErr: true,
+		},
+		{
+			name:        "invalid jsonPath",
+			rule:        validRule(func(r *RelationshipRule) { r.JSONPath = "{.spec.secretName" }),
+			expectedErr: true,
+		},
+		{
+			name:        "jsonPath with multiple expressions",
+			rule:        validRule(func(r *RelationshipRule) { r.JSONPath = "{.spec.secretName}{.spec.secretNames}" }),
+			expectedErr: true,
+		},
+		{
+			name: "invalid target namespaceJSONPath",
+			rule: validRule(func(r *RelationshipRule) {
+				r.Target.Namespace = RelationshipRuleNamespaceJSONPath
+				r.Target.NamespaceJSONPath = "{.spec.secretNamespace"
+			}),
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := compileRelationshipRule(tt.rule)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to compile rule: %v", err)
+			}
+			if r.Target.MatchBy != tt.expectedMatchBy {
+				t.Fatalf("expected matchBy \"%s\" got \"%s\"", tt.expectedMatchBy, r.Target.MatchBy)
+			}
+			if r.Target.Namespace != tt.expectedNamespace {
+				t.Fatalf("expected namespace \"%s\" got \"%s\"", tt.expectedNamespace, r.Target.Namespace)
+			}
+		})
+	}
+}
+
+func TestRegisterRelationshipRules(t *testing.T) {
+	t.Parallel()
+
+	// Rules are registered for a resource type that isn't used by other tests,
+	// so that they don't affect other tests running in parallel
+	gk := schema.GroupKind{Group: "example.com", Kind: "Widget"}
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		delete(registry.rules, gk)
+	})
+
+	objects := newTestObjects(t, `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: w
+  namespace: default
+spec:
+  secretName: foo
+  secretNames:
+  - foo
+  - bar
+  secretRef:
+    name: foo
+    namespace: other
+  secretUID: Secret/default/bar
+  nodeName: n1
+  podSelector:
+    matchLabels:
+      app: foo
+  podLabels:
+    app: bar
+`, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: default
+`, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: bar
+  namespace: default
+`, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: other
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: a
+  namespace: default
+  labels:
+    app: foo
+spec:
+  containers:
+  - name: app
+    image: nginx
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: b
+  namespace: default
+  labels:
+    app: bar
+spec:
+  containers:
+  - name: app
+    image: nginx
+`, `
+apiVersion: v1
+kind: Node
+metadata:
+  name: n1
+`)
+
+	rule := func(jsonPath string, target RelationshipRuleTarget, dependent bool) RelationshipRule {
+		return RelationshipRule{
+			Group:        gk.Group,
+			Kind:         gk.Kind,
+			JSONPath:     jsonPath,
+			Target:       target,
+			Relationship: "Widget",
+			Dependent:    dependent,
+		}
+	}
+
+	// Subtests aren't run in parallel since each of them replaces the rules
+	// registered by the previous one
+	tests := []struct {
+		name     string
+		rules    []RelationshipRule
+		expected []string
+	}{
+		{
+			name: "match by name",
+			rules: []RelationshipRule{
+				rule("{.spec.secretName}", RelationshipRuleTarget{Kind: "Secret"}, false),
+			},
+			expected: []string{
+				"Secret/default/foo -[Widget]-> Widget/default/w",
+			},
+		},
+		{
+			name: "match by name of dependents",
+			rules: []RelationshipRule{
+				rule("{.spec.secretName}", RelationshipRuleTarget{Kind: "Secret"}, true),
+			},
+			expected: []string{
+				"Widget/default/w -[Widget]-> Secret/default/foo",
+			},
+		},
+		{
+			name: "match by names in a list",
+			rules: []RelationshipRule{
+				rule("{.spec.secretNames[*]}", RelationshipRuleTarget{Kind: "Secret"}, false),
+			},
+			expected: []string{
+				"Secret/default/bar -[Widget]-> Widget/default/w",
+				"Secret/default/foo -[Widget]-> Widget/default/w",
+			},
+		},
+		{
+			name: "match by name in namespace from JSONPath",
+			rules: []RelationshipRule{
+				rule("{.spec.secretRef.name}", RelationshipRuleTarget{
+					Kind:              "Secret",
+					Namespace:         RelationshipRuleNamespaceJSONPath,
+					NamespaceJSONPath: "{.spec.secretRef.namespace}",
+				}, false),
+			},
+			expected: []string{
+				"Secret/other/foo -[Widget]-> Widget/default/w",
+			},
+		},
+		{
+			name: "match by name with relaxed JSONPaths",
+			rules: []RelationshipRule{
+				rule("spec.secretRef.name", RelationshipRuleTarget{
+					Kind:              "Secret",
+					Namespace:         RelationshipRuleNamespaceJSONPath,
+					NamespaceJSONPath: ".spec.secretRef.namespace",
+				}, false),
+			},
+			expected: []string{
+				"Secret/other/foo -[Widget]-> Widget/default/w",
+			},
+		},
+		{
+			name: "match by name of cluster-scoped objects",
+			rules: []RelationshipRule{
+				rule("{.spec.nodeName}", RelationshipRuleTarget{Kind: "Node", Namespace: RelationshipRuleNamespaceCluster}, false),
+			},
+			expected: []string{
+				"Node/n1 -[Widget]-> Widget/default/w",
+			},
+		},
+		{
+			name: "match by LabelSelector",
+			rules: []RelationshipRule{
+				rule("{.spec.podSelector}", RelationshipRuleTarget{Kind: "Pod", MatchBy: RelationshipRuleMatchByLabelSelector}, false),
+			},
+			expected: []string{
+				"Pod/default/a -[Widget]-> Widget/default/w",
+			},
+		},
+		{
+			name: "match by map of labels",
+			rules: []RelationshipRule{
+				rule("{.spec.podLabels}", RelationshipRuleTarget{Kind: "Pod", MatchBy: RelationshipRuleMatchByLabelSelector}, false),
+			},
+			expected: []string{
+				"Pod/default/b -[Widget]-> Widget/default/w",
+			},
+		},
+		{
+			name: "match by UID",
+			rules: []RelationshipRule{
+				rule("{.spec.secretUID}", RelationshipRuleTarget{Kind: "Secret", MatchBy: RelationshipRuleMatchByUID}, false),
+			},
+			expected: []string{
+				"Secret/default/bar -[Widget]-> Widget/default/w",
+			},
+		},
+		{
+			name: "missing field",
+			rules: []RelationshipRule{
+				rule("{.spec.unknown}", RelationshipRuleTarget{Kind: "Secret"}, false),
+			},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// Registering the same rules more than once should replace them
+			for i := 0; i < 2; i++ {
+				if err := RegisterRelationshipRules(tt.rules); err != nil {
+					t.Fatalf("failed to register rules: %v", err)
+				}
+			}
+			registry.mu.RLock()
+			count := len(registry.rules[gk])
+			registry.mu.RUnlock()
+			if count != len(tt.rules) {
+				t.Fatalf("expected %d registered rules got %d", len(tt.rules), count)
+			}
+
+			nodeMap := resolveTestGraph(t, objects, ResolveOptions{})
+			if edges := getTestEdges(nodeMap); !reflect.DeepEqual(edges, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, edges)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package graph
+
+import (
+	storagev1 "k8s.io/api/storage/v1"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Hardcode "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1.GroupName"
+// as "snapshot.storage.k8s.io" so we don't need import the entire
+// github.com/kubernetes-csi/external-snapshotter package.
+const VolumeSnapshotGroupName = "snapshot.storage.k8s.io"
+
+const (
+	// Kubernetes VolumeSnapshot & VolumeSnapshotContent relationships.
+	RelationshipVolumeSnapshotClass            Relationship = "VolumeSnapshotClass"
+	RelationshipVolumeSnapshotContent          Relationship = "VolumeSnapshotContent"
+	RelationshipVolumeSnapshotContentClass     Relationship = "VolumeSnapshotContentClass"
+	RelationshipVolumeSnapshotContentCSIDriver Relationship = "VolumeSnapshotContentCSIDriver"
+	RelationshipVolumeSnapshotSource           Relationship = "VolumeSnapshotSource"
+
+	// Kubernetes VolumeSnapshotClass relationships.
+	RelationshipVolumeSnapshotClassCSIDriver Relationship = "VolumeSnapshotClassCSIDriver"
+)
+
+// getVolumeSnapshotRelationships returns a map of relationships that this
+// VolumeSnapshot has with other objects, based on what was referenced in its
+// manifest.
+//
+//nolint:unparam
+func getVolumeSnapshotRelationships(n *Node) (*RelationshipMap, error) {
+	var ref ObjectReference
+	ns := n.Namespace
+	result := NewRelationshipMap()
+
+	// RelationshipVolumeSnapshotClass
+	if vsc := n.GetNestedString("spec", "volumeSnapshotClassName"); len(vsc) != 0 {
+		ref = ObjectReference{Group: VolumeSnapshotGroupName, Kind: "VolumeSnapshotClass", Name: vsc}
+		result.AddDependencyByKey(ref.Key(), RelationshipVolumeSnapshotClass)
+	}
+
+	// RelationshipVolumeSnapshotContent
+	for _, fields := range [][]string{
+		{"spec", "source", "volumeSnapshotContentName"},
+		{"status", "boundVolumeSnapshotContentName"},
+	} {
+		if vsc := n.GetNestedString(fields...); len(vsc) != 0 {
+			ref = ObjectReference{Group: VolumeSnapshotGroupName, Kind: "VolumeSnapshotContent", Name: vsc}
+			result.AddDependencyByKey(ref.Key(), RelationshipVolumeSnapshotContent)
+		}
+	}
+
+	// RelationshipVolumeSnapshotSource
+	if pvc := n.GetNestedString("spec", "source", "persistentVolumeClaimName"); len(pvc) != 0 {
+		ref = ObjectReference{Kind: "PersistentVolumeClaim", Name: pvc, Namespace: ns}
+		result.AddDependencyByKey(ref.Key(), RelationshipVolumeSnapshotSource)
+	}
+
+	return &result, nil
+}
+
+// getVolumeSnapshotClassRelationships returns a map of relationships that this
+// VolumeSnapshotClass has with other objects, based on what was referenced in
+// its manifest.
+//
+//nolint:unparam
+func getVolumeSnapshotClassRelationships(n *Node) (*RelationshipMap, error) {
+	var ref ObjectReference
+	result := NewRelationshipMap()
+
+	// RelationshipVolumeSnapshotClassCSIDriver
+	if d := n.GetNestedString("driver"); len(d) != 0 {
+		ref = ObjectReference{Group: storagev1.GroupName, Kind: "CSIDriver", Name: d}
+		result.AddDependencyByKey(ref.Key(), RelationshipVolumeSnapshotClassCSIDriver)
+	}
+
+	return &result, nil
+}
+
+// getVolumeSnapshotContentRelationships returns a map of relationships that
+// this VolumeSnapshotContent has with other objects, based on what was
+// referenced in its manifest.
+//
+//nolint:unparam
+func getVolumeSnapshotContentRelationships(n *Node) (*RelationshipMap, error) {
+	var ref ObjectReference
+	result := NewRelationshipMap()
+
+	// RelationshipVolumeSnapshotContent
+	if m, found, _ := unstructuredv1.NestedMap(n.UnstructuredContent(), "spec", "volumeSnapshotRef"); found {
+		uid, _, _ := unstructuredv1.NestedString(m, "uid")
+		name, _, _ := unstructuredv1.NestedString(m, "name")
+		namespace, _, _ := unstructuredv1.NestedString(m, "namespace")
+		switch {
+		case len(uid) != 0:
+			result.AddDependentByUID(types.UID(uid), RelationshipVolumeSnapshotContent)
+		case len(name) != 0:
+			ref = ObjectReference{Group: VolumeSnapshotGroupName, Kind: "VolumeSnapshot", Name: name, Namespace: namespace}
+			result.AddDependentByKey(ref.Key(), RelationshipVolumeSnapshotContent)
+		}
+	}
+
+	// RelationshipVolumeSnapshotContentClass
+	if vsc := n.GetNestedString("spec", "volumeSnapshotClassName"); len(vsc) != 0 {
+		ref = ObjectReference{Group: VolumeSnapshotGroupName, Kind: "VolumeSnapshotClass", Name: vsc}
+		result.AddDependencyByKey(ref.Key(), RelationshipVolumeSnapshotContentClass)
+	}
+
+	// RelationshipVolumeSnapshotContentCSIDriver
+	if d := n.GetNestedString("spec", "driver"); len(d) != 0 {
+		ref = ObjectReference{Group: storagev1.GroupName, Kind: "CSIDriver", Name: d}
+		result.AddDependencyByKey(ref.Key(), RelationshipVolumeSnapshotContentCSIDriver)
+	}
+
+	return &result, nil
+}
@@ -17,14 +17,17 @@ const (
 
 // Flags composes common printer flag structs used in the command.
 type Flags struct {
+	GraphFlags         *GraphPrintFlags
 	HumanReadableFlags *HumanPrintFlags
+	JSONYamlFlags      *JSONYamlPrintFlags
 	OutputFormat       *string
 }
 
 // AddFlags receives a *pflag.FlagSet reference and binds flags related to
-// human-readable printing to it.
+// printing to it.
 func (f *Flags) AddFlags(flags *pflag.FlagSet) {
 	f.HumanReadableFlags.AddFlags(flags)
+	f.JSONYamlFlags.AddFlags(flags)
 
 	if f.OutputFormat != nil {
 		flags.StringVarP(f.OutputFormat, flagOutputFormat, flagOutputFormatShorthand, *f.OutputFormat, fmt.Sprintf("Output format. One of: %s.", strings.Join(f.AllowedFormats(), "|")))
@@ -35,6 +38,8 @@ func (f *Flags) AddFlags(flags *pflag.FlagSet) {
 func (f *Flags) AllowedFormats() []string {
 	formats := []string{}
 	formats = append(formats, f.HumanReadableFlags.AllowedFormats()...)
+	formats = append(formats, f.JSONYamlFlags.AllowedFormats()...)
+	formats = append(formats, f.GraphFlags.AllowedFormats()...)
 	return formats
 }
 
@@ -50,6 +55,18 @@ func (f *Flags) EnsureWithGroup() {
 	f.HumanReadableFlags.EnsureWithGroup()
 }
 
+// IsGraphOutputFormat returns true if provided output format is a graph
+// format.
+func (f *Flags) IsGraphOutputFormat(outputFormat string) bool {
+	return f.GraphFlags.IsSupportedOutputFormat(outputFormat)
+}
+
+// IsJSONYamlOutputFormat returns true if provided output format is a JSON or
+// YAML format.
+func (f *Flags) IsJSONYamlOutputFormat(outputFormat string) bool {
+	return f.JSONYamlFlags.IsSupportedOutputFormat(outputFormat)
+}
+
 // IsTableOutputFormat returns true if provided output format is a table format.
 func (f *Flags) IsTableOutputFormat(outputFormat string) bool {
 	return f.HumanReadableFlags.IsSupportedOutputFormat(outputFormat)
@@ -77,6 +94,18 @@ func (f *Flags) ToPrinter(client client.Interface) (Interface, error) {
 			outputFormat: outputFormat,
 			client:       client,
 		}
+	case f.IsJSONYamlOutputFormat(outputFormat):
+		var err error
+		printer, err = f.JSONYamlFlags.ToPrinter(outputFormat)
+		if err != nil {
+			return nil, err
+		}
+	case f.IsGraphOutputFormat(outputFormat):
+		var err error
+		printer, err = f.GraphFlags.ToPrinter(outputFormat)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, genericclioptions.NoCompatiblePrinterError{
 			AllowedFormats: f.AllowedFormats(),
@@ -87,13 +116,14 @@ func (f *Flags) ToPrinter(client client.Interface) (Interface, error) {
 	return printer, nil
 }
 
-// NewFlags returns flags associated with human-readable printing, with default
-// values set.
+// NewFlags returns flags associated with printing, with default values set.
 func NewFlags() *Flags {
 	outputFormat := ""
 
 	return &Flags{
+		GraphFlags:         NewGraphPrintFlags(),
 		OutputFormat:       &outputFormat,
 		HumanReadableFlags: NewHumanPrintFlags(),
+		JSONYamlFlags:      NewJSONYamlPrintFlags(),
 	}
 }
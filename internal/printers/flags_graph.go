@@ -0,0 +1,59 @@
+package printers
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// List of supported graph output formats.
+const (
+	outputFormatDot      = "dot"
+	outputFormatHTML     = "html"
+	outputFormatMermaid  = "mermaid"
+	outputFormatPlantUML = "plantuml"
+)
+
+// GraphPrintFlags provides default flags necessary for printing the
+// relationship graph in a graph description language or as an HTML report.
+// Given the following flag values, a printer can be requested that knows how
+// to handle printing based on these values.
+type GraphPrintFlags struct{}
+
+// AllowedFormats returns the list of graph output formats.
+func (f *GraphPrintFlags) AllowedFormats() []string {
+	return []string{
+		outputFormatDot,
+		outputFormatMermaid,
+		outputFormatPlantUML,
+		outputFormatHTML,
+	}
+}
+
+// IsSupportedOutputFormat returns true if provided output format is supported.
+func (f *GraphPrintFlags) IsSupportedOutputFormat(outputFormat string) bool {
+	return sets.NewString(f.AllowedFormats()...).Has(outputFormat)
+}
+
+// ToPrinter receives an outputFormat and returns a printer capable of handling
+// graph output.
+func (f *GraphPrintFlags) ToPrinter(outputFormat string) (Interface, error) {
+	if !f.IsSupportedOutputFormat(outputFormat) {
+		return nil, genericclioptions.NoCompatiblePrinterError{
+			Options:        f,
+			AllowedFormats: f.AllowedFormats(),
+		}
+	}
+	if outputFormat == outputFormatHTML {
+		return &htmlPrinter{}, nil
+	}
+	p := &graphPrinter{
+		outputFormat: outputFormat,
+	}
+	return p, nil
+}
+
+// NewGraphPrintFlags returns flags associated with graph printing, with default
+// values set.
+func NewGraphPrintFlags() *GraphPrintFlags {
+	return &GraphPrintFlags{}
+}
@@ -0,0 +1,75 @@
+package printers
+
+import (
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	flagShowObject = "show-object"
+)
+
+// List of supported JSON & YAML output formats.
+const (
+	outputFormatJSON = "json"
+	outputFormatYAML = "yaml"
+)
+
+// JSONYamlPrintFlags provides default flags necessary for printing the
+// relationship tree in JSON or YAML. Given the following flag values, a printer
+// can be requested that knows how to handle printing based on these values.
+type JSONYamlPrintFlags struct {
+	ShowObject *bool
+}
+
+// AllowedFormats returns the list of JSON & YAML output formats.
+func (f *JSONYamlPrintFlags) AllowedFormats() []string {
+	return []string{
+		outputFormatJSON,
+		outputFormatYAML,
+	}
+}
+
+// IsSupportedOutputFormat returns true if provided output format is supported.
+func (f *JSONYamlPrintFlags) IsSupportedOutputFormat(outputFormat string) bool {
+	return sets.NewString(f.AllowedFormats()...).Has(outputFormat)
+}
+
+// ToPrinter receives an outputFormat and returns a printer capable of handling
+// JSON or YAML output.
+func (f *JSONYamlPrintFlags) ToPrinter(outputFormat string) (Interface, error) {
+	if !f.IsSupportedOutputFormat(outputFormat) {
+		return nil, genericclioptions.NoCompatiblePrinterError{
+			Options:        f,
+			AllowedFormats: f.AllowedFormats(),
+		}
+	}
+	showObject := false
+	if f.ShowObject != nil {
+		showObject = *f.ShowObject
+	}
+	p := &jsonYamlPrinter{
+		outputFormat: outputFormat,
+		showObject:   showObject,
+	}
+	return p, nil
+}
+
+// AddFlags receives a *pflag.FlagSet reference and binds flags related to JSON
+// & YAML printing to it.
+func (f *JSONYamlPrintFlags) AddFlags(flags *pflag.FlagSet) {
+	if f.ShowObject != nil {
+		flags.BoolVar(f.ShowObject, flagShowObject, *f.ShowObject, "When using the json or yaml output format, include the full manifest of each object (default omit object manifests)")
+	}
+}
+
+// NewJSONYamlPrintFlags returns flags associated with JSON & YAML printing,
+// with default values set.
+func NewJSONYamlPrintFlags() *JSONYamlPrintFlags {
+	showObject := false
+
+	return &JSONYamlPrintFlags{
+		ShowObject: &showObject,
+	}
+}
@@ -26,8 +26,49 @@ func lessGroupKind(lhs, rhs schema.GroupKind) bool {
 	return lhs.String() < rhs.String()
 }
 
+// createSortDepsFn creates a function that sorts the list of dependency or
+// dependent UIDs based on the underlying object in following order:
+// Namespace, Kind, Group, Name
+func createSortDepsFn(nodeMap graph.NodeMap) func(d map[types.UID]graph.RelationshipSet) []types.UID {
+	return func(d map[types.UID]graph.RelationshipSet) []types.UID {
+		nodes, ix := make(graph.NodeList, len(d)), 0
+		for uid := range d {
+			nodes[ix] = nodeMap[uid]
+			ix++
+		}
+		sort.Sort(nodes)
+		sortedUIDs := make([]types.UID, len(d))
+		for ix, node := range nodes {
+			sortedUIDs[ix] = node.UID
+		}
+		return sortedUIDs
+	}
+}
+
+// getRootNodes returns the nodes of the provided root UIDs, in the order they're
+// provided with duplicates removed.
+func getRootNodes(nodeMap graph.NodeMap, rootUIDs []types.UID) ([]*graph.Node, error) {
+	if len(rootUIDs) == 0 {
+		return nil, fmt.Errorf("at least one requested object must be provided")
+	}
+	roots := make([]*graph.Node, 0, len(rootUIDs))
+	uidSet := map[types.UID]struct{}{}
+	for _, uid := range rootUIDs {
+		root, ok := nodeMap[uid]
+		if !ok {
+			return nil, fmt.Errorf("requested object (uid: %s) not found in list of fetched objects", uid)
+		}
+		if _, ok := uidSet[uid]; ok {
+			continue
+		}
+		uidSet[uid] = struct{}{}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
 type Interface interface {
-	Print(w io.Writer, nodeMap graph.NodeMap, rootUID types.UID, maxDepth uint, depsIsDependencies bool) error
+	Print(w io.Writer, nodeMap graph.NodeMap, rootUIDs []types.UID, maxDepth uint, depsIsDependencies bool) error
 }
 
 type tablePrinter struct {
@@ -39,10 +80,10 @@ type tablePrinter struct {
 	client client.Interface
 }
 
-func (p *tablePrinter) Print(w io.Writer, nodeMap graph.NodeMap, rootUID types.UID, maxDepth uint, depsIsDependencies bool) error {
-	root, ok := nodeMap[rootUID]
-	if !ok {
-		return fmt.Errorf("requested object (uid: %s) not found in list of fetched objects", rootUID)
+func (p *tablePrinter) Print(w io.Writer, nodeMap graph.NodeMap, rootUIDs []types.UID, maxDepth uint, depsIsDependencies bool) error {
+	roots, err := getRootNodes(nodeMap, rootUIDs)
+	if err != nil {
+		return err
 	}
 
 	if p.configFlags.IsSplitOutputFormat(p.outputFormat) {
@@ -52,17 +93,17 @@ func (p *tablePrinter) Print(w io.Writer, nodeMap graph.NodeMap, rootUID types.U
 		return p.printTablesByGK(w, nodeMap, maxDepth)
 	}
 
-	return p.printTable(w, nodeMap, root, maxDepth, depsIsDependencies)
+	return p.printTable(w, nodeMap, roots, maxDepth, depsIsDependencies)
 }
 
-func (p *tablePrinter) printTable(w io.Writer, nodeMap graph.NodeMap, root *graph.Node, maxDepth uint, depsIsDependencies bool) error {
+func (p *tablePrinter) printTable(w io.Writer, nodeMap graph.NodeMap, roots []*graph.Node, maxDepth uint, depsIsDependencies bool) error {
 	// Generate Table to print
 	showGroup := false
 	if sg := p.configFlags.ShowGroup; sg != nil {
 		showGroup = *sg
 	}
 	showGroupFn := createShowGroupFn(nodeMap, showGroup, maxDepth)
-	t, err := nodeMapToTable(nodeMap, root, maxDepth, depsIsDependencies, showGroupFn)
+	t, err := nodeMapToTable(nodeMap, roots, maxDepth, depsIsDependencies, showGroupFn)
 	if err != nil {
 		return err
 	}
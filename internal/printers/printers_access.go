@@ -0,0 +1,76 @@
+package printers
+
+import (
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+// accessColumnDefinitions holds table column definition for the grant paths
+// of an object.
+var accessColumnDefinitions = []metav1.TableColumnDefinition{
+	{Name: "Name", Type: "string", Format: "name", Description: metav1.ObjectMeta{}.SwaggerDoc()["name"]},
+	{Name: "Relationships", Type: "array", Description: "The relationships this object has with its parent."},
+}
+
+// PrintAccess prints the provided grant paths as a tree, starting from the
+// object with the provided UID & followed by the roles, bindings, subjects &
+// pods that each path goes through.
+func PrintAccess(w io.Writer, nodeMap graph.NodeMap, rootUID types.UID, showGroup, showNamespace, noHeaders bool) error {
+	root, ok := nodeMap[rootUID]
+	if !ok {
+		return fmt.Errorf("requested object (uid: %s) not found in list of fetched objects", rootUID)
+	}
+
+	showGroupFn := createShowGroupFn(nodeMap, showGroup, 0)
+	sortDepsFn := createSortDepsFn(nodeMap)
+	uidSet := map[types.UID]struct{}{}
+	var addRowsFn func(node *graph.Node, rset graph.RelationshipSet, namePrefix, prefix string)
+	rows := []metav1.TableRow{}
+	addRowsFn = func(node *graph.Node, rset graph.RelationshipSet, namePrefix, prefix string) {
+		relationships := []string{}
+		if rset != nil {
+			relationships = rset.List()
+		}
+		rows = append(rows, metav1.TableRow{
+			Object: runtime.RawExtension{Object: node.DeepCopyObject()},
+			Cells: []interface{}{
+				namePrefix + getNodeName(node, showGroupFn),
+				relationships,
+			},
+		})
+
+		// Guard against possible cycles
+		if _, ok := uidSet[node.UID]; ok {
+			return
+		}
+		uidSet[node.UID] = struct{}{}
+
+		depUIDs := sortDepsFn(node.Dependents)
+		lastIx := len(depUIDs) - 1
+		for ix, childUID := range depUIDs {
+			childPrefix, depPrefix := prefix+"├── ", prefix+"│   "
+			if ix == lastIx {
+				childPrefix, depPrefix = prefix+"└── ", prefix+"    "
+			}
+			addRowsFn(nodeMap[childUID], node.Dependents[childUID], childPrefix, depPrefix)
+		}
+	}
+	addRowsFn(root, nil, "", "")
+
+	table := &metav1.Table{
+		ColumnDefinitions: accessColumnDefinitions,
+		Rows:              rows,
+	}
+	p := printers.NewTablePrinter(printers.PrintOptions{
+		NoHeaders:     noHeaders,
+		WithNamespace: showNamespace,
+	})
+	return p.PrintObj(table, w)
+}
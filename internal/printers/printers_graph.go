@@ -0,0 +1,308 @@
+package printers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+// Fill colors used for styling nodes based on their ready status.
+const (
+	colorNotReady = "#f8d7da"
+	colorPartial  = "#fff3cd"
+	colorReady    = "#d4edda"
+	colorUnknown  = "#ffffff"
+)
+
+// graphEdge represents a single relationship between two nodes in the
+// relationship graph.
+type graphEdge struct {
+	From         int
+	To           int
+	Relationship graph.Relationship
+}
+
+// relationshipGraph is the relationship tree flattened into a graph, where
+// each object is only included once.
+type relationshipGraph struct {
+	Nodes graph.NodeList
+	Edges []graphEdge
+}
+
+type graphPrinter struct {
+	outputFormat string
+}
+
+func (p *graphPrinter) Print(w io.Writer, nodeMap graph.NodeMap, rootUIDs []types.UID, maxDepth uint, depsIsDependencies bool) error {
+	roots, err := getRootNodes(nodeMap, rootUIDs)
+	if err != nil {
+		return err
+	}
+	g, err := nodeMapToGraph(nodeMap, roots, maxDepth, depsIsDependencies)
+	if err != nil {
+		return err
+	}
+	showGroupFn := createShowGroupFn(nodeMap, false, maxDepth)
+
+	var buf bytes.Buffer
+	switch p.outputFormat {
+	case outputFormatDot:
+		printDot(&buf, g, showGroupFn)
+	case outputFormatMermaid:
+		printMermaid(&buf, g, showGroupFn)
+	case outputFormatPlantUML:
+		printPlantUML(&buf, g, showGroupFn)
+	default:
+		return fmt.Errorf("output format \"%s\" not supported", p.outputFormat)
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// nodeMapToGraph converts the provided nodes & either their dependencies or
+// dependents into a graph. Unlike the relationship tree, objects that are
+// reachable through multiple paths are only included once.
+func nodeMapToGraph(nodeMap graph.NodeMap, roots []*graph.Node, maxDepth uint, depsIsDependencies bool) (*relationshipGraph, error) {
+	// Find all objects within the maximum depth using a breadth-first search, so
+	// that each object is visited at the smallest depth it can be reached
+	depthByUID := map[types.UID]uint{}
+	queue := []*graph.Node{}
+	for _, root := range roots {
+		depthByUID[root.UID] = 0
+		queue = append(queue, root)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		depth := depthByUID[node.UID]
+		if maxDepth != 0 && depth >= maxDepth {
+			continue
+		}
+		for uid := range node.GetDeps(depsIsDependencies) {
+			if _, ok := depthByUID[uid]; ok {
+				continue
+			}
+			dep, ok := nodeMap[uid]
+			if !ok {
+				return nil, fmt.Errorf("dependent object (uid: %s) not found in list of fetched objects", uid)
+			}
+			depthByUID[uid] = depth + 1
+			queue = append(queue, dep)
+		}
+	}
+
+	// Sort nodes in the following order: Roots, Namespace, Kind, Group, Name
+	rootSet := map[types.UID]struct{}{}
+	for _, root := range roots {
+		rootSet[root.UID] = struct{}{}
+	}
+	var nodes graph.NodeList
+	for uid := range depthByUID {
+		if _, ok := rootSet[uid]; !ok {
+			nodes = append(nodes, nodeMap[uid])
+		}
+	}
+	sort.Sort(nodes)
+	nodes = append(graph.NodeList(roots), nodes...)
+	ixByUID := make(map[types.UID]int, len(nodes))
+	for ix, node := range nodes {
+		ixByUID[node.UID] = ix
+	}
+
+	// Collect relationships between the objects, omitting the ones originating
+	// from objects at the maximum depth
+	var edges []graphEdge
+	sortDepsFn := createSortDepsFn(nodeMap)
+	for ix, node := range nodes {
+		if maxDepth != 0 && depthByUID[node.UID] >= maxDepth {
+			continue
+		}
+		deps := node.GetDeps(depsIsDependencies)
+		for _, uid := range sortDepsFn(deps) {
+			for _, r := range deps[uid].List() {
+				edges = append(edges, graphEdge{From: ix, To: ixByUID[uid], Relationship: graph.Relationship(r)})
+			}
+		}
+	}
+
+	return &relationshipGraph{Nodes: nodes, Edges: edges}, nil
+}
+
+// getNodeColor returns the fill color of a node based on its ready status.
+func getNodeColor(ready string) string {
+	switch ready {
+	case "True":
+		return colorReady
+	case "False":
+		return colorNotReady
+	}
+	if tokens := strings.SplitN(ready, "/", 2); len(tokens) == 2 {
+		current, err1 := strconv.Atoi(tokens[0])
+		desired, err2 := strconv.Atoi(tokens[1])
+		switch {
+		case err1 != nil || err2 != nil:
+			return colorUnknown
+		case current == desired:
+			return colorReady
+		case current == 0:
+			return colorNotReady
+		default:
+			return colorPartial
+		}
+	}
+	return colorUnknown
+}
+
+// getNodeLabelLines returns the lines of text describing the provided node in
+// a graph, which consists of its name & ready status.
+func getNodeLabelLines(node *graph.Node, showGroupFn func(kind string) bool) ([]string, string) {
+	lines := []string{getNodeName(node, showGroupFn)}
+	ready, status := getNodeReadyStatus(node)
+	if s := strings.TrimSpace(strings.Join([]string{ready, status}, " ")); len(s) > 0 {
+		lines = append(lines, s)
+	}
+	return lines, ready
+}
+
+// groupNodesByNamespace returns the sorted list of namespaces of the objects in
+// the provided graph, along with the node indexes mapped by their namespace.
+// Cluster-scoped objects are mapped to an empty namespace.
+func groupNodesByNamespace(g *relationshipGraph) ([]string, map[string][]int) {
+	var namespaces []string
+	ixsByNS := map[string][]int{}
+	for ix, node := range g.Nodes {
+		ns := node.Namespace
+		if _, ok := ixsByNS[ns]; !ok && len(ns) > 0 {
+			namespaces = append(namespaces, ns)
+		}
+		ixsByNS[ns] = append(ixsByNS[ns], ix)
+	}
+	sort.Strings(namespaces)
+	return namespaces, ixsByNS
+}
+
+// escapeDot escapes the provided string to be used within a quoted string in
+// the DOT language.
+func escapeDot(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	return strings.ReplaceAll(s, "\"", "\\\"")
+}
+
+// printDot prints the provided graph in the DOT language.
+func printDot(w io.Writer, g *relationshipGraph, showGroupFn func(kind string) bool) {
+	printNode := func(indent string, ix int, node *graph.Node) {
+		lines, ready := getNodeLabelLines(node, showGroupFn)
+		for i := range lines {
+			lines[i] = escapeDot(lines[i])
+		}
+		fmt.Fprintf(w, "%sn%d [label=\"%s\", fillcolor=\"%s\"];\n", indent, ix, strings.Join(lines, "\\n"), getNodeColor(ready))
+	}
+
+	fmt.Fprintf(w, "digraph \"kube-lineage\" {\n")
+	fmt.Fprintf(w, "  rankdir=LR;\n")
+	fmt.Fprintf(w, "  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	fmt.Fprintf(w, "  edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	// Group namespaced objects into clusters by their namespace
+	namespaces, ixsByNS := groupNodesByNamespace(g)
+	for _, ix := range ixsByNS[""] {
+		printNode("  ", ix, g.Nodes[ix])
+	}
+	for _, ns := range namespaces {
+		fmt.Fprintf(w, "  subgraph \"cluster_%s\" {\n", escapeDot(ns))
+		fmt.Fprintf(w, "    label=\"%s\";\n", escapeDot(ns))
+		for _, ix := range ixsByNS[ns] {
+			printNode("    ", ix, g.Nodes[ix])
+		}
+		fmt.Fprintf(w, "  }\n")
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  n%d -> n%d [label=\"%s\"];\n", e.From, e.To, escapeDot(string(e.Relationship)))
+	}
+	fmt.Fprintf(w, "}\n")
+}
+
+// escapeMermaid escapes the provided string to be used within a quoted string
+// in a Mermaid diagram.
+func escapeMermaid(s string) string {
+	return strings.NewReplacer("\"", "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+// printMermaid prints the provided graph as a Mermaid flowchart.
+func printMermaid(w io.Writer, g *relationshipGraph, showGroupFn func(kind string) bool) {
+	printNode := func(indent string, ix int, node *graph.Node) {
+		lines, ready := getNodeLabelLines(node, showGroupFn)
+		for i := range lines {
+			lines[i] = escapeMermaid(lines[i])
+		}
+		fmt.Fprintf(w, "%sn%d[\"%s\"]\n", indent, ix, strings.Join(lines, "<br/>"))
+		fmt.Fprintf(w, "%sstyle n%d fill:%s\n", indent, ix, getNodeColor(ready))
+	}
+
+	fmt.Fprintf(w, "flowchart LR\n")
+
+	// Group namespaced objects into subgraphs by their namespace
+	namespaces, ixsByNS := groupNodesByNamespace(g)
+	for _, ix := range ixsByNS[""] {
+		printNode("  ", ix, g.Nodes[ix])
+	}
+	for nsIx, ns := range namespaces {
+		fmt.Fprintf(w, "  subgraph ns%d[\"%s\"]\n", nsIx, escapeMermaid(ns))
+		for _, ix := range ixsByNS[ns] {
+			printNode("    ", ix, g.Nodes[ix])
+		}
+		fmt.Fprintf(w, "  end\n")
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  n%d -->|\"%s\"| n%d\n", e.From, escapeMermaid(string(e.Relationship)), e.To)
+	}
+}
+
+// escapePlantUML escapes the provided string to be used within a quoted string
+// or a link label in a PlantUML diagram.
+func escapePlantUML(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "'", "\n", "\\n").Replace(s)
+}
+
+// printPlantUML prints the provided graph as a PlantUML diagram.
+func printPlantUML(w io.Writer, g *relationshipGraph, showGroupFn func(kind string) bool) {
+	printNode := func(indent string, ix int, node *graph.Node) {
+		lines, ready := getNodeLabelLines(node, showGroupFn)
+		for i := range lines {
+			lines[i] = escapePlantUML(lines[i])
+		}
+		fmt.Fprintf(w, "%srectangle \"%s\" as n%d %s\n", indent, strings.Join(lines, "\\n"), ix, getNodeColor(ready))
+	}
+
+	fmt.Fprintf(w, "@startuml\n")
+	fmt.Fprintf(w, "left to right direction\n")
+	fmt.Fprintf(w, "skinparam defaultFontName Helvetica\n")
+	fmt.Fprintf(w, "skinparam rectangle {\n  RoundCorner 10\n}\n")
+
+	// Group namespaced objects into packages by their namespace
+	namespaces, ixsByNS := groupNodesByNamespace(g)
+	for _, ix := range ixsByNS[""] {
+		printNode("", ix, g.Nodes[ix])
+	}
+	for _, ns := range namespaces {
+		fmt.Fprintf(w, "package \"%s\" {\n", escapePlantUML(ns))
+		for _, ix := range ixsByNS[ns] {
+			printNode("  ", ix, g.Nodes[ix])
+		}
+		fmt.Fprintf(w, "}\n")
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "n%d --> n%d : %s\n", e.From, e.To, escapePlantUML(string(e.Relationship)))
+	}
+	fmt.Fprintf(w, "@enduml\n")
+}
@@ -0,0 +1,110 @@
+package printers
+
+import (
+	_ "embed" //nolint:gci
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+//go:embed templates/report.html.tmpl
+var htmlReportTemplate string
+
+// htmlReportNode is the representation of an object in the HTML report.
+type htmlReportNode struct {
+	Name      string `json:"name"`
+	Group     string `json:"group"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Ready     string `json:"ready"`
+	Status    string `json:"status"`
+	Age       string `json:"age"`
+	Color     string `json:"color"`
+}
+
+// htmlReportEdge is the representation of the relationships between two
+// objects in the HTML report.
+type htmlReportEdge struct {
+	From          int      `json:"from"`
+	To            int      `json:"to"`
+	Relationships []string `json:"relationships"`
+}
+
+// htmlReport contains the data embedded in the HTML report.
+type htmlReport struct {
+	Title              string           `json:"title"`
+	DepsIsDependencies bool             `json:"depsIsDependencies"`
+	Roots              []int            `json:"roots"`
+	Nodes              []htmlReportNode `json:"nodes"`
+	Edges              []htmlReportEdge `json:"edges"`
+}
+
+type htmlPrinter struct{}
+
+func (p *htmlPrinter) Print(w io.Writer, nodeMap graph.NodeMap, rootUIDs []types.UID, maxDepth uint, depsIsDependencies bool) error {
+	roots, err := getRootNodes(nodeMap, rootUIDs)
+	if err != nil {
+		return err
+	}
+	g, err := nodeMapToGraph(nodeMap, roots, maxDepth, depsIsDependencies)
+	if err != nil {
+		return err
+	}
+	showGroupFn := createShowGroupFn(nodeMap, false, maxDepth)
+
+	// Root objects are the first nodes of the graph
+	titles := make([]string, len(roots))
+	report := htmlReport{
+		DepsIsDependencies: depsIsDependencies,
+		Roots:              make([]int, len(roots)),
+		Nodes:              make([]htmlReportNode, len(g.Nodes)),
+	}
+	for ix, root := range roots {
+		titles[ix] = getNodeName(root, showGroupFn)
+		report.Roots[ix] = ix
+	}
+	report.Title = strings.Join(titles, ", ")
+	for ix, node := range g.Nodes {
+		ready, status := getNodeReadyStatus(node)
+		age := ""
+		if node.Unstructured != nil {
+			age = translateTimestampSince(node.GetCreationTimestamp())
+		}
+		report.Nodes[ix] = htmlReportNode{
+			Name:      getNodeName(node, showGroupFn),
+			Group:     node.Group,
+			Kind:      node.Kind,
+			Namespace: node.Namespace,
+			Ready:     ready,
+			Status:    status,
+			Age:       age,
+			Color:     getNodeColor(ready),
+		}
+	}
+	// Merge relationships between the same pair of objects into a single edge
+	for _, e := range g.Edges {
+		if n := len(report.Edges); n > 0 && report.Edges[n-1].From == e.From && report.Edges[n-1].To == e.To {
+			report.Edges[n-1].Relationships = append(report.Edges[n-1].Relationships, string(e.Relationship))
+			continue
+		}
+		report.Edges = append(report.Edges, htmlReportEdge{
+			From:          e.From,
+			To:            e.To,
+			Relationships: []string{string(e.Relationship)},
+		})
+	}
+
+	tmpl, err := template.New("report").Parse(htmlReportTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, map[string]interface{}{
+		"GeneratedAt": time.Now().UTC().Format(time.RFC3339),
+		"Report":      report,
+	})
+}
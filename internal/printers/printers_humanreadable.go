@@ -2,7 +2,6 @@ package printers
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
@@ -37,6 +36,12 @@ var (
 		{Name: "Age", Type: "string", Description: metav1.ObjectMeta{}.SwaggerDoc()["creationTimestamp"]},
 		{Name: "Relationships", Type: "array", Description: "The relationships this object has with its parent.", Priority: -1},
 	}
+	// aggregatedRulesColumnDefinition holds table column definition for the
+	// effective policy rules of aggregated ClusterRoles, which is only added to
+	// tables containing an aggregated ClusterRole.
+	aggregatedRulesColumnDefinition = metav1.TableColumnDefinition{
+		Name: "Aggregated Rules", Type: "array", Description: "The effective policy rules of an aggregated ClusterRole & the ClusterRoles that contributed them.", Priority: -1,
+	}
 	// objectReadyReasonJSONPath is the JSON path to get a Kubernetes object's
 	// "Ready" condition reason.
 	objectReadyReasonJSONPath = newJSONPath("reason", "{.status.conditions[?(@.type==\"Ready\")].reason}")
@@ -435,20 +440,9 @@ func getVolumeAttachmentReadyStatus(u *unstructuredv1.Unstructured) (string, str
 	return ready, status, nil
 }
 
-// nodeToTableRow converts the provided node into a table row.
-//nolint:funlen,gocognit,goconst
-func nodeToTableRow(node *graph.Node, rset graph.RelationshipSet, namePrefix string, showGroupFn func(kind string) bool) metav1.TableRow {
-	var name, ready, status, age string
-	var relationships interface{}
-
-	switch {
-	case len(node.Kind) == 0:
-		name = node.Name
-	case len(node.Group) > 0 && showGroupFn(node.Kind):
-		name = fmt.Sprintf("%s%s.%s/%s", namePrefix, node.Kind, node.Group, node.Name)
-	default:
-		name = fmt.Sprintf("%s%s/%s", namePrefix, node.Kind, node.Name)
-	}
+// getNodeReadyStatus returns the ready & status value of the provided node.
+func getNodeReadyStatus(node *graph.Node) (string, string) {
+	var ready, status string
 	switch {
 	case node.Group == corev1.GroupName && node.Kind == "Event":
 		ready, status, _ = getEventCoreReadyStatus(node.Unstructured)
@@ -475,6 +469,57 @@ func nodeToTableRow(node *graph.Node, rset graph.RelationshipSet, namePrefix str
 	case node.Unstructured != nil:
 		ready, status, _ = getObjectReadyStatus(node.Unstructured)
 	}
+
+	return ready, status
+}
+
+// formatAggregatedRules returns the provided aggregated rules in a compact
+// form, eg. "get,list pods,deployments.apps (view)".
+func formatAggregatedRules(rules []graph.AggregatedPolicyRule) []string {
+	result := make([]string, 0, len(rules))
+	for _, r := range rules {
+		var targets []string
+		for _, g := range r.APIGroups {
+			for _, res := range r.Resources {
+				if len(g) > 0 {
+					res = fmt.Sprintf("%s.%s", res, g)
+				}
+				targets = append(targets, res)
+			}
+		}
+		if len(r.ResourceNames) > 0 {
+			targets = append(targets, fmt.Sprintf("[%s]", strings.Join(r.ResourceNames, ",")))
+		}
+		targets = append(targets, r.NonResourceURLs...)
+		result = append(result, fmt.Sprintf("%s %s (%s)", strings.Join(r.Verbs, ","), strings.Join(targets, ","), r.Source))
+	}
+	return result
+}
+
+// getNodeName returns the name of the provided node to be printed, prefixed by
+// its Kind or GroupKind.
+func getNodeName(node *graph.Node, showGroupFn func(kind string) bool) string {
+	switch {
+	case len(node.Kind) == 0:
+		return node.Name
+	case len(node.Group) > 0 && showGroupFn(node.Kind):
+		return fmt.Sprintf("%s.%s/%s", node.Kind, node.Group, node.Name)
+	default:
+		return fmt.Sprintf("%s/%s", node.Kind, node.Name)
+	}
+}
+
+// nodeToTableRow converts the provided node into a table row.
+//nolint:goconst
+func nodeToTableRow(node *graph.Node, rset graph.RelationshipSet, namePrefix string, showGroupFn func(kind string) bool, showAggregatedRules bool) metav1.TableRow {
+	var name, ready, status, age string
+	var relationships interface{}
+
+	name = getNodeName(node, showGroupFn)
+	if len(node.Kind) > 0 {
+		name = namePrefix + name
+	}
+	ready, status = getNodeReadyStatus(node)
 	if len(ready) == 0 {
 		ready = cellNotApplicable
 	}
@@ -485,54 +530,77 @@ func nodeToTableRow(node *graph.Node, rset graph.RelationshipSet, namePrefix str
 	if rset != nil {
 		relationships = rset.List()
 	}
+	cells := []interface{}{
+		name,
+		ready,
+		status,
+		age,
+		relationships,
+	}
+	if showAggregatedRules {
+		var aggregatedRules interface{} = cellNotApplicable
+		if node.AggregatedRules != nil {
+			aggregatedRules = formatAggregatedRules(node.AggregatedRules)
+		}
+		cells = append(cells, aggregatedRules)
+	}
 
 	return metav1.TableRow{
 		Object: runtime.RawExtension{Object: node.DeepCopyObject()},
-		Cells: []interface{}{
-			name,
-			ready,
-			status,
-			age,
-			relationships,
-		},
+		Cells:  cells,
+	}
+}
+
+// getObjectColumnDefinitions returns the table column definitions for
+// Kubernetes objects, including the aggregated rules column only if requested.
+func getObjectColumnDefinitions(showAggregatedRules bool) []metav1.TableColumnDefinition {
+	if !showAggregatedRules {
+		return objectColumnDefinitions
+	}
+	columns := make([]metav1.TableColumnDefinition, 0, len(objectColumnDefinitions)+1)
+	columns = append(columns, objectColumnDefinitions...)
+	return append(columns, aggregatedRulesColumnDefinition)
+}
+
+// hasAggregatedRules determines whether any node in the provided node map up to
+// the provided depth is an aggregated ClusterRole.
+func hasAggregatedRules(nodeMap graph.NodeMap, maxDepth uint) bool {
+	for _, node := range nodeMap {
+		if maxDepth != 0 && node.Depth > maxDepth {
+			continue
+		}
+		if node.AggregatedRules != nil {
+			return true
+		}
 	}
+	return false
 }
 
-// nodeMapToTable converts the provided node & either its dependencies or
-// dependents into table rows.
+// nodeMapToTable converts the provided nodes & either their dependencies or
+// dependents into table rows. Objects shared by multiple nodes only have their
+// dependencies or dependents listed once.
 func nodeMapToTable(
 	nodeMap graph.NodeMap,
-	root *graph.Node,
+	roots []*graph.Node,
 	maxDepth uint,
 	depsIsDependencies bool,
 	showGroupFn func(kind string) bool) (*metav1.Table, error) {
-	// Sorts the list of UIDs based on the underlying object in following order:
-	// Namespace, Kind, Group, Name
-	sortDepsFn := func(d map[types.UID]graph.RelationshipSet) []types.UID {
-		nodes, ix := make(graph.NodeList, len(d)), 0
-		for uid := range d {
-			nodes[ix] = nodeMap[uid]
-			ix++
-		}
-		sort.Sort(nodes)
-		sortedUIDs := make([]types.UID, len(d))
-		for ix, node := range nodes {
-			sortedUIDs[ix] = node.UID
-		}
-		return sortedUIDs
-	}
+	sortDepsFn := createSortDepsFn(nodeMap)
+	showAggregatedRules := hasAggregatedRules(nodeMap, maxDepth)
 
 	var rows []metav1.TableRow
-	row := nodeToTableRow(root, nil, "", showGroupFn)
 	uidSet := map[types.UID]struct{}{}
-	depRows, err := nodeDepsToTableRows(nodeMap, uidSet, root, "", 1, maxDepth, depsIsDependencies, sortDepsFn, showGroupFn)
-	if err != nil {
-		return nil, err
+	for _, root := range roots {
+		row := nodeToTableRow(root, nil, "", showGroupFn, showAggregatedRules)
+		depRows, err := nodeDepsToTableRows(nodeMap, uidSet, root, "", 1, maxDepth, depsIsDependencies, sortDepsFn, showGroupFn, showAggregatedRules)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+		rows = append(rows, depRows...)
 	}
-	rows = append(rows, row)
-	rows = append(rows, depRows...)
 	table := metav1.Table{
-		ColumnDefinitions: objectColumnDefinitions,
+		ColumnDefinitions: getObjectColumnDefinitions(showAggregatedRules),
 		Rows:              rows,
 	}
 
@@ -550,7 +618,8 @@ func nodeDepsToTableRows(
 	maxDepth uint,
 	depsIsDependencies bool,
 	sortDepsFn func(d map[types.UID]graph.RelationshipSet) []types.UID,
-	showGroupFn func(kind string) bool) ([]metav1.TableRow, error) {
+	showGroupFn func(kind string) bool,
+	showAggregatedRules bool) ([]metav1.TableRow, error) {
 	rows := make([]metav1.TableRow, 0, len(nodeMap))
 
 	// Guard against possible cycles
@@ -578,10 +647,10 @@ func nodeDepsToTableRows(
 		if !ok {
 			return nil, fmt.Errorf("dependent object (uid: %s) not found", childUID)
 		}
-		row := nodeToTableRow(child, rset, childPrefix, showGroupFn)
+		row := nodeToTableRow(child, rset, childPrefix, showGroupFn, showAggregatedRules)
 		rows = append(rows, row)
 		if maxDepth == 0 || depth < maxDepth {
-			depRows, err := nodeDepsToTableRows(nodeMap, uidSet, child, depPrefix, depth+1, maxDepth, depsIsDependencies, sortDepsFn, showGroupFn)
+			depRows, err := nodeDepsToTableRows(nodeMap, uidSet, child, depPrefix, depth+1, maxDepth, depsIsDependencies, sortDepsFn, showGroupFn, showAggregatedRules)
 			if err != nil {
 				return nil, err
 			}
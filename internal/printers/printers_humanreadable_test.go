@@ -0,0 +1,77 @@
+package printers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNodeMapToTable(t *testing.T) {
+	t.Parallel()
+
+	nodeMap := newTestNodeMap()
+	roots, err := getRootNodes(nodeMap, []types.UID{"Secret/default/foo", "Secret/default/bar"})
+	if err != nil {
+		t.Fatalf("failed to get root nodes: %v", err)
+	}
+
+	tests := []struct {
+		name               string
+		maxDepth           uint
+		depsIsDependencies bool
+		expected           []string
+	}{
+		{
+			name:     "multiple roots",
+			maxDepth: 0,
+			expected: []string{
+				"Secret/foo []",
+				"└── Pod/app [PodVolume]",
+				"    └── Service/app [Service]",
+				"Secret/bar []",
+				"└── Pod/app [PodVolume]",
+			},
+		},
+		{
+			name:     "multiple roots with max depth",
+			maxDepth: 1,
+			expected: []string{
+				"Secret/foo []",
+				"└── Pod/app [PodVolume]",
+				"Secret/bar []",
+				"└── Pod/app [PodVolume]",
+			},
+		},
+		{
+			name:               "multiple roots with dependencies",
+			maxDepth:           0,
+			depsIsDependencies: true,
+			expected: []string{
+				"Secret/foo []",
+				"Secret/bar []",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			showGroupFn := func(string) bool { return false }
+			table, err := nodeMapToTable(nodeMap, roots, tt.maxDepth, tt.depsIsDependencies, showGroupFn)
+			if err != nil {
+				t.Fatalf("failed to convert node map to table: %v", err)
+			}
+			// Only the name & relationships columns are compared
+			var rows []string
+			for _, r := range table.Rows {
+				rows = append(rows, fmt.Sprintf("%s %v", r.Cells[0], r.Cells[4]))
+			}
+			if !reflect.DeepEqual(rows, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, rows)
+			}
+		})
+	}
+}
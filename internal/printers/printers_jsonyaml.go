@@ -0,0 +1,143 @@
+package printers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+// treeNode is the serializable representation of an object in the
+// relationship tree.
+type treeNode struct {
+	Group           string                       `json:"group"`
+	Version         string                       `json:"version"`
+	Kind            string                       `json:"kind"`
+	Namespace       string                       `json:"namespace,omitempty"`
+	Name            string                       `json:"name"`
+	UID             types.UID                    `json:"uid,omitempty"`
+	Depth           uint                         `json:"depth"`
+	Relationships   []string                     `json:"relationships,omitempty"`
+	AggregatedRules []graph.AggregatedPolicyRule `json:"aggregatedRules,omitempty"`
+	Object          map[string]interface{}       `json:"object,omitempty"`
+	Repeated        bool                         `json:"repeated,omitempty"`
+	Dependencies    []treeNode                   `json:"dependencies,omitempty"`
+	Dependents      []treeNode                   `json:"dependents,omitempty"`
+}
+
+// treeList is the serializable representation of the relationship trees of
+// the requested objects.
+type treeList struct {
+	Items []treeNode `json:"items"`
+}
+
+type jsonYamlPrinter struct {
+	outputFormat string
+	showObject   bool
+}
+
+func (p *jsonYamlPrinter) Print(w io.Writer, nodeMap graph.NodeMap, rootUIDs []types.UID, maxDepth uint, depsIsDependencies bool) error {
+	roots, err := getRootNodes(nodeMap, rootUIDs)
+	if err != nil {
+		return err
+	}
+
+	// Objects shared by multiple roots only have their dependencies or
+	// dependents included once, the trees are always printed as a list
+	// regardless of the number of roots to keep the output shape consistent
+	sortDepsFn := createSortDepsFn(nodeMap)
+	uidSet := map[types.UID]struct{}{}
+	trees := make([]treeNode, 0, len(roots))
+	for _, root := range roots {
+		t, err := p.nodeToTreeNode(nodeMap, uidSet, root, nil, 0, maxDepth, depsIsDependencies, sortDepsFn)
+		if err != nil {
+			return err
+		}
+		trees = append(trees, t)
+	}
+	tree := treeList{Items: trees}
+
+	var data []byte
+	switch p.outputFormat {
+	case outputFormatJSON:
+		data, err = json.MarshalIndent(tree, "", "    ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	case outputFormatYAML:
+		data, err = yaml.Marshal(tree)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("output format \"%s\" not supported", p.outputFormat)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// nodeToTreeNode converts the provided node & either its dependencies or
+// dependents into a tree of serializable nodes.
+func (p *jsonYamlPrinter) nodeToTreeNode(
+	nodeMap graph.NodeMap,
+	uidSet map[types.UID]struct{},
+	node *graph.Node,
+	rset graph.RelationshipSet,
+	depth uint,
+	maxDepth uint,
+	depsIsDependencies bool,
+	sortDepsFn func(d map[types.UID]graph.RelationshipSet) []types.UID) (treeNode, error) {
+	result := treeNode{
+		Group:           node.Group,
+		Version:         node.Version,
+		Kind:            node.Kind,
+		Namespace:       node.Namespace,
+		Name:            node.Name,
+		UID:             node.UID,
+		Depth:           depth,
+		AggregatedRules: node.AggregatedRules,
+	}
+	if rset != nil {
+		result.Relationships = rset.List()
+	}
+	if p.showObject && node.Unstructured != nil && len(node.Kind) > 0 {
+		result.Object = node.UnstructuredContent()
+	}
+
+	if maxDepth != 0 && depth >= maxDepth {
+		return result, nil
+	}
+	// Guard against possible cycles, objects whose dependencies or dependents
+	// are already included earlier in the output are marked as repeated
+	if _, ok := uidSet[node.UID]; ok {
+		result.Repeated = true
+		return result, nil
+	}
+	uidSet[node.UID] = struct{}{}
+
+	deps := node.GetDeps(depsIsDependencies)
+	children := make([]treeNode, 0, len(deps))
+	for _, childUID := range sortDepsFn(deps) {
+		child, ok := nodeMap[childUID]
+		if !ok {
+			return result, fmt.Errorf("dependent object (uid: %s) not found in list of fetched objects", childUID)
+		}
+		c, err := p.nodeToTreeNode(nodeMap, uidSet, child, deps[childUID], depth+1, maxDepth, depsIsDependencies, sortDepsFn)
+		if err != nil {
+			return result, err
+		}
+		children = append(children, c)
+	}
+	if depsIsDependencies {
+		result.Dependencies = children
+	} else {
+		result.Dependents = children
+	}
+
+	return result, nil
+}
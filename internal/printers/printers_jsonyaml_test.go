@@ -0,0 +1,104 @@
+package printers
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+func TestJSONYamlPrinterPrint(t *testing.T) {
+	t.Parallel()
+
+	nodeMap := newTestNodeMap()
+	rootUIDs := []types.UID{"Secret/default/foo", "Secret/default/bar", "Secret/default/foo"}
+	secretFn := func(name string, dependents ...treeNode) treeNode {
+		return treeNode{
+			Version:    "v1",
+			Kind:       "Secret",
+			Namespace:  "default",
+			Name:       name,
+			UID:        types.UID("Secret/default/" + name),
+			Depth:      0,
+			Dependents: dependents,
+		}
+	}
+	podFn := func(repeated bool, dependents ...treeNode) treeNode {
+		return treeNode{
+			Version:       "v1",
+			Kind:          "Pod",
+			Namespace:     "default",
+			Name:          "app",
+			UID:           "Pod/default/app",
+			Depth:         1,
+			Relationships: []string{"PodVolume"},
+			Repeated:      repeated,
+			Dependents:    dependents,
+		}
+	}
+	service := treeNode{
+		Version:       "v1",
+		Kind:          "Service",
+		Namespace:     "default",
+		Name:          "app",
+		UID:           "Service/default/app",
+		Depth:         2,
+		Relationships: []string{"Service"},
+	}
+
+	tests := []struct {
+		name         string
+		outputFormat string
+		maxDepth     uint
+		expected     treeList
+	}{
+		{
+			name:         "multiple roots in json",
+			outputFormat: outputFormatJSON,
+			maxDepth:     0,
+			expected: treeList{Items: []treeNode{
+				secretFn("foo", podFn(false, service)),
+				secretFn("bar", podFn(true)),
+			}},
+		},
+		{
+			name:         "multiple roots in yaml",
+			outputFormat: outputFormatYAML,
+			maxDepth:     0,
+			expected: treeList{Items: []treeNode{
+				secretFn("foo", podFn(false, service)),
+				secretFn("bar", podFn(true)),
+			}},
+		},
+		{
+			name:         "multiple roots with max depth",
+			outputFormat: outputFormatJSON,
+			maxDepth:     1,
+			expected: treeList{Items: []treeNode{
+				secretFn("foo", podFn(false)),
+				secretFn("bar", podFn(false)),
+			}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			p := &jsonYamlPrinter{outputFormat: tt.outputFormat}
+			if err := p.Print(&buf, nodeMap, rootUIDs, tt.maxDepth, false); err != nil {
+				t.Fatalf("failed to print: %v", err)
+			}
+			var actual treeList
+			if err := yaml.Unmarshal(buf.Bytes(), &actual); err != nil {
+				t.Fatalf("failed to decode output: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Fatalf("expected %+v got %+v", tt.expected, actual)
+			}
+		})
+	}
+}
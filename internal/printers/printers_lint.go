@@ -0,0 +1,74 @@
+package printers
+
+import (
+	"io"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+// missingReferenceColumnDefinitions holds table column definition for
+// references to missing Kubernetes objects.
+var missingReferenceColumnDefinitions = []metav1.TableColumnDefinition{
+	{Name: "Name", Type: "string", Format: "name", Description: "The object that references the missing object."},
+	{Name: "Missing", Type: "string", Description: "The object that is referenced but doesn't exist."},
+	{Name: "Relationships", Type: "array", Description: "The relationships that produced the reference."},
+}
+
+// PrintMissingReferences prints a table of every reference to a missing object
+// in the provided node map, along with the relationship types that produced
+// each reference.
+func PrintMissingReferences(w io.Writer, nodeMap graph.NodeMap, showGroup, showNamespace, noHeaders bool) error {
+	var missingNodes graph.NodeList
+	for _, node := range nodeMap {
+		if node.Missing {
+			missingNodes = append(missingNodes, node)
+		}
+	}
+	sort.Sort(missingNodes)
+
+	// Missing objects are either dependencies or dependents of the objects that
+	// reference them, depending on the relationship type
+	showGroupFn := createShowGroupFn(nodeMap, showGroup, 0)
+	sortDepsFn := createSortDepsFn(nodeMap)
+	var rows []metav1.TableRow
+	for _, missing := range missingNodes {
+		refs := map[types.UID]graph.RelationshipSet{}
+		for _, deps := range []map[types.UID]graph.RelationshipSet{missing.Dependents, missing.Dependencies} {
+			for uid, rset := range deps {
+				if _, ok := refs[uid]; !ok {
+					refs[uid] = graph.RelationshipSet{}
+				}
+				for r := range rset {
+					refs[uid][r] = struct{}{}
+				}
+			}
+		}
+		for _, uid := range sortDepsFn(refs) {
+			node := nodeMap[uid]
+			rows = append(rows, metav1.TableRow{
+				Object: runtime.RawExtension{Object: node.DeepCopyObject()},
+				Cells: []interface{}{
+					getNodeName(node, showGroupFn),
+					getNodeName(missing, showGroupFn),
+					refs[uid].List(),
+				},
+			})
+		}
+	}
+
+	table := &metav1.Table{
+		ColumnDefinitions: missingReferenceColumnDefinitions,
+		Rows:              rows,
+	}
+	p := printers.NewTablePrinter(printers.PrintOptions{
+		NoHeaders:     noHeaders,
+		WithNamespace: showNamespace,
+	})
+	return p.PrintObj(table, w)
+}
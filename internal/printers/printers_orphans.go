@@ -0,0 +1,39 @@
+package printers
+
+import (
+	"io"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+// PrintOrphans prints a table of the provided orphaned objects, which are
+// objects that have neither dependencies nor dependents.
+func PrintOrphans(w io.Writer, nodeMap graph.NodeMap, showGroup, showNamespace, noHeaders bool) error {
+	orphans, ix := make(graph.NodeList, len(nodeMap)), 0
+	for _, node := range nodeMap {
+		orphans[ix] = node
+		ix++
+	}
+	sort.Sort(orphans)
+
+	showGroupFn := createShowGroupFn(nodeMap, showGroup, 0)
+	showAggregatedRules := hasAggregatedRules(nodeMap, 0)
+	rows := make([]metav1.TableRow, len(orphans))
+	for ix, node := range orphans {
+		rows[ix] = nodeToTableRow(node, nil, "", showGroupFn, showAggregatedRules)
+	}
+
+	table := &metav1.Table{
+		ColumnDefinitions: getObjectColumnDefinitions(showAggregatedRules),
+		Rows:              rows,
+	}
+	p := printers.NewTablePrinter(printers.PrintOptions{
+		NoHeaders:     noHeaders,
+		WithNamespace: showNamespace,
+	})
+	return p.PrintObj(table, w)
+}
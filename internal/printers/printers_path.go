@@ -0,0 +1,52 @@
+package printers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+// PrintPaths prints each of the provided relationship paths on a single line,
+// where an arrow points from an object towards its dependent. For example,
+// "Secret/foo -[PodVolume]-> Pod/bar" indicates that the pod "bar" depends on
+// the secret "foo". Objects outside of the provided namespace are suffixed with
+// their namespace.
+func PrintPaths(w io.Writer, paths []graph.Path, namespace string, showGroup bool) error {
+	nodeMap := graph.NodeMap{}
+	for _, path := range paths {
+		for _, e := range path {
+			nodeMap[e.From.UID] = e.From
+			nodeMap[e.To.UID] = e.To
+		}
+	}
+	showGroupFn := createShowGroupFn(nodeMap, showGroup, 0)
+	getNameFn := func(node *graph.Node) string {
+		name := getNodeName(node, showGroupFn)
+		if len(node.Namespace) > 0 && node.Namespace != namespace {
+			name = fmt.Sprintf("%s (%s)", name, node.Namespace)
+		}
+		return name
+	}
+
+	var buf bytes.Buffer
+	for _, path := range paths {
+		if len(path) == 0 {
+			continue
+		}
+		buf.WriteString(getNameFn(path[0].From))
+		for _, e := range path {
+			rels := strings.Join(e.Relationships.List(), ",")
+			if e.IsDependency {
+				fmt.Fprintf(&buf, " <-[%s]- %s", rels, getNameFn(e.To))
+			} else {
+				fmt.Fprintf(&buf, " -[%s]-> %s", rels, getNameFn(e.To))
+			}
+		}
+		buf.WriteString("\n")
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
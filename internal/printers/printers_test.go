@@ -0,0 +1,114 @@
+package printers
+
+import (
+	"reflect"
+	"testing"
+
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+// newTestNode returns a node of a core object in the default namespace, using
+// "<kind>/default/<name>" as its UID.
+func newTestNode(kind, name string, depth uint) *graph.Node {
+	uid := types.UID(kind + "/default/" + name)
+	u := &unstructuredv1.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind(kind)
+	u.SetNamespace("default")
+	u.SetName(name)
+	u.SetUID(uid)
+	return &graph.Node{
+		Unstructured: u,
+		UID:          uid,
+		Version:      "v1",
+		Kind:         kind,
+		Namespaced:   true,
+		Namespace:    "default",
+		Name:         name,
+		Dependencies: map[types.UID]graph.RelationshipSet{},
+		Dependents:   map[types.UID]graph.RelationshipSet{},
+		Depth:        depth,
+	}
+}
+
+// newTestNodeMap returns a node map where the Secrets "foo" & "bar" are both
+// used by the Pod "app", which is selected by the Service "app".
+func newTestNodeMap() graph.NodeMap {
+	foo := newTestNode("Secret", "foo", 0)
+	bar := newTestNode("Secret", "bar", 0)
+	pod := newTestNode("Pod", "app", 1)
+	svc := newTestNode("Service", "app", 2)
+	addEdgeFn := func(n, dependent *graph.Node, r graph.Relationship) {
+		n.AddDependent(dependent.UID, r)
+		dependent.AddDependency(n.UID, r)
+	}
+	addEdgeFn(foo, pod, graph.RelationshipPodVolume)
+	addEdgeFn(bar, pod, graph.RelationshipPodVolume)
+	addEdgeFn(pod, svc, graph.RelationshipService)
+
+	nodeMap := graph.NodeMap{}
+	for _, n := range []*graph.Node{foo, bar, pod, svc} {
+		nodeMap[n.UID] = n
+	}
+	return nodeMap
+}
+
+func TestGetRootNodes(t *testing.T) {
+	t.Parallel()
+
+	nodeMap := newTestNodeMap()
+	tests := []struct {
+		name        string
+		rootUIDs    []types.UID
+		expected    []types.UID
+		expectedErr bool
+	}{
+		{
+			name:     "single root",
+			rootUIDs: []types.UID{"Secret/default/foo"},
+			expected: []types.UID{"Secret/default/foo"},
+		},
+		{
+			name:     "multiple roots with duplicates",
+			rootUIDs: []types.UID{"Secret/default/foo", "Secret/default/bar", "Secret/default/foo"},
+			expected: []types.UID{"Secret/default/foo", "Secret/default/bar"},
+		},
+		{
+			name:        "no roots",
+			rootUIDs:    nil,
+			expectedErr: true,
+		},
+		{
+			name:        "unknown root",
+			rootUIDs:    []types.UID{"Secret/default/foo", "unknown"},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			roots, err := getRootNodes(nodeMap, tt.rootUIDs)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get root nodes: %v", err)
+			}
+			var uids []types.UID
+			for _, r := range roots {
+				uids = append(uids, r.UID)
+			}
+			if !reflect.DeepEqual(uids, tt.expected) {
+				t.Fatalf("expected %q got %q", tt.expected, uids)
+			}
+		})
+	}
+}
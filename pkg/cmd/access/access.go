@@ -0,0 +1,238 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/tohjustin/kube-lineage/internal/client"
+	"github.com/tohjustin/kube-lineage/internal/graph"
+	"github.com/tohjustin/kube-lineage/internal/log"
+	lineageprinters "github.com/tohjustin/kube-lineage/internal/printers"
+	lineageutil "github.com/tohjustin/kube-lineage/pkg/cmd/util"
+)
+
+var (
+	cmdPath    string
+	cmdName    = "access"
+	cmdUse     = "%CMD% VERB TYPE[.VERSION][.GROUP]/NAME [flags]"
+	cmdExample = templates.Examples(`
+		# List the subjects that can get the secret named "foo"
+		%CMD_PATH% get secret/foo
+
+		# List the subjects that can delete the deployment named "bar" in namespace "baz", along with the pods running as them in any namespace
+		%CMD_PATH% delete deployment/bar --namespace=baz --all-namespaces
+
+		# List the subjects that can update the configmap named "foo" using objects from a cluster dump
+		%CMD_PATH% update configmap/foo --from-file=cluster-dump.tar.gz`)
+	cmdShort = "Display the subjects that can perform a verb on a Kubernetes object"
+	cmdLong  = templates.LongDesc(`
+		Display the subjects that can perform a verb on a Kubernetes object.
+
+		Each grant path is printed as a tree, starting from the Role or ClusterRole
+		whose policy rules allow the verb, followed by the RoleBinding or
+		ClusterRoleBinding referencing the role, the ServiceAccounts, Users & Groups
+		bound to it & finally the Pods running as each ServiceAccount.
+
+		VERB is a Kubernetes API request verb (e.g. get, list, update, delete).
+		TYPE is a Kubernetes resource. Shortcuts and groups will be resolved.
+		NAME is the name of a particular Kubernetes resource.`)
+)
+
+// CmdOptions contains all the options for running the access command.
+type CmdOptions struct {
+	// RequestVerb represents the verb that subjects should be able to perform
+	// on the requested object.
+	RequestVerb string
+	// RequestObject represents the object to find grant paths of, in the
+	// <resource>/<name> form.
+	RequestObject string
+	Flags         *Flags
+
+	Namespace   string
+	Client      client.Interface
+	ClientFlags *client.Flags
+	GraphFlags  *lineageutil.GraphFlags
+
+	genericclioptions.IOStreams
+}
+
+// NewCmd returns an initialized Command for the access command.
+func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *cobra.Command {
+	o := &CmdOptions{
+		Flags:       NewFlags(),
+		ClientFlags: client.NewFlags(),
+		GraphFlags:  lineageutil.NewGraphFlags(),
+		IOStreams:   streams,
+	}
+
+	f := cmdutil.NewFactory(o.ClientFlags)
+	util.SetFactoryForCompletion(f)
+
+	if len(name) > 0 {
+		cmdName = name
+	}
+	cmdPath = cmdName
+	if len(parentCmdPath) > 0 {
+		cmdPath = parentCmdPath + " " + cmdName
+	}
+	cmd := &cobra.Command{
+		Use:                   strings.ReplaceAll(cmdUse, "%CMD%", cmdName),
+		Example:               strings.ReplaceAll(cmdExample, "%CMD_PATH%", cmdPath),
+		Short:                 cmdShort,
+		Long:                  cmdLong,
+		Args:                  cobra.MaximumNArgs(2),
+		DisableFlagsInUseLine: true,
+		DisableSuggestions:    true,
+		SilenceUsage:          true,
+		Run: func(c *cobra.Command, args []string) {
+			klog.V(4).Infof("Version: %s", c.Root().Version)
+			cmdutil.CheckErr(o.Complete(c, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return compGetVerbList(toComplete)
+			case 1:
+				return compGetResourceList(o, f, cmd, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	// Setup flags
+	o.Flags.AddFlags(cmd.Flags())
+	o.ClientFlags.AddFlags(cmd.Flags())
+	o.GraphFlags.AddFlags(cmd.Flags())
+	log.AddFlags(cmd.Flags())
+
+	// Setup flag completion function
+	o.Flags.RegisterFlagCompletionFunc(cmd, f)
+	o.ClientFlags.RegisterFlagCompletionFunc(cmd, f)
+
+	return cmd
+}
+
+// Complete completes all the required options for the access command.
+func (o *CmdOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) == 2 {
+		o.RequestVerb = args[0]
+		o.RequestObject = args[1]
+	}
+
+	// Register relationship rules & setup client
+	o.Client, o.Namespace, err = o.GraphFlags.Complete(o.ClientFlags)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates all the required options for the access command.
+func (o *CmdOptions) Validate() error {
+	if len(o.RequestVerb) == 0 || len(o.RequestObject) == 0 {
+		return fmt.Errorf("a verb & a resource must be specified as <verb> <resource>/<name>\nSee '%s -h' for help and examples", cmdPath)
+	}
+	if tokens := strings.SplitN(o.RequestObject, "/", 2); len(tokens) != 2 || len(tokens[0]) == 0 || len(tokens[1]) == 0 {
+		return fmt.Errorf("arguments in <resource>/<name> form must have a single resource and name\nSee '%s -h' for help and examples", cmdPath)
+	}
+
+	klog.V(4).Infof("Namespace: %s", o.Namespace)
+	klog.V(4).Infof("RequestObject: %v", o.RequestObject)
+	klog.V(4).Infof("RequestVerb: %v", o.RequestVerb)
+	klog.V(4).Infof("Flags.AllNamespaces: %t", *o.Flags.AllNamespaces)
+	klog.V(4).Infof("Flags.NoHeaders: %t", *o.Flags.NoHeaders)
+	klog.V(4).Infof("Flags.Scopes: %v", *o.Flags.Scopes)
+	klog.V(4).Infof("Flags.ShowGroup: %t", *o.Flags.ShowGroup)
+	klog.V(4).Infof("ClientFlags.Context: %s", *o.ClientFlags.Context)
+	klog.V(4).Infof("ClientFlags.Namespace: %s", *o.ClientFlags.Namespace)
+	klog.V(4).Infof("GraphFlags.FromFiles: %v", *o.GraphFlags.FromFiles)
+	klog.V(4).Infof("GraphFlags.RelationshipRules: %s", *o.GraphFlags.RelationshipRules)
+
+	return nil
+}
+
+// Run implements all the necessary functionality for the access command.
+func (o *CmdOptions) Run() error {
+	ctx := context.Background()
+
+	// First check if Kubernetes cluster is reachable
+	if err := o.Client.IsReachable(); err != nil {
+		return err
+	}
+
+	// Fetch the provided object to ensure it exists before proceeding
+	root, err := o.getObject(ctx, o.RequestObject)
+	if err != nil {
+		return err
+	}
+
+	// Fetch resources in the cluster
+	isClusterScopeRequest := o.Flags.AllNamespaces != nil && *o.Flags.AllNamespaces
+	objs, _, err := lineageutil.ListObjects(ctx, o.Client, lineageutil.ListOptions{
+		Namespace:     o.Namespace,
+		AllNamespaces: isClusterScopeRequest,
+		Scopes:        *o.Flags.Scopes,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Include requested object into objects to handle cases where user has
+	// access to get the requested object but unable to list its resource type
+	objs = append(objs, *root)
+
+	// Resolve the relationships between all objects, with the users & groups
+	// bound to roles included as synthetic objects, & find every grant path of
+	// the requested object
+	nodeMap, err := graph.ResolveGraph(o.Client.GetMapper(), objs, graph.ResolveOptions{IncludeSubjects: true})
+	if err != nil {
+		return err
+	}
+	accessMap, err := graph.ResolveAccess(nodeMap, root.GetUID(), o.RequestVerb)
+	if err != nil {
+		return err
+	}
+	if len(accessMap) <= 1 {
+		fmt.Fprintf(o.ErrOut, "No subjects can %s \"%s\".\n", o.RequestVerb, o.RequestObject)
+		return nil
+	}
+	nsSet := map[string]struct{}{}
+	for _, node := range accessMap {
+		if len(node.Namespace) > 0 {
+			nsSet[node.Namespace] = struct{}{}
+		}
+	}
+
+	// Print output
+	showNamespace := len(nsSet) > 1 || isClusterScopeRequest
+	return lineageprinters.PrintAccess(o.Out, accessMap, root.GetUID(), *o.Flags.ShowGroup, showNamespace, *o.Flags.NoHeaders)
+}
+
+// getObject fetches the object of the provided <resource>/<name> string in the
+// current namespace.
+func (o *CmdOptions) getObject(ctx context.Context, s string) (*unstructuredv1.Unstructured, error) {
+	tokens := strings.SplitN(s, "/", 2)
+	api, err := o.Client.ResolveAPIResource(tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	return o.Client.Get(ctx, tokens[1], client.GetOptions{
+		APIResource: *api,
+		Namespace:   o.Namespace,
+	})
+}
@@ -0,0 +1,59 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/cmd/get"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// compGetResourceList provides dynamic auto-completion for resources in the
+// <resource>/<name> form.
+func compGetResourceList(opts *CmdOptions, f cmdutil.Factory, cmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cobra.CompDebugln(fmt.Sprintf("compGetResourceList with \"%s\"", toComplete), false)
+
+	// Complete resource names once the resource type is provided
+	if tokens := strings.SplitN(toComplete, "/", 2); len(tokens) == 2 {
+		var choices []string
+		for _, name := range get.CompGetResource(f, cmd, tokens[0], tokens[1]) {
+			choices = append(choices, tokens[0]+"/"+name)
+		}
+		return choices, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if err := opts.Complete(nil, nil); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var choices []string
+	apis, err := opts.Client.GetAPIResources(context.Background())
+	if err != nil {
+		cobra.CompErrorln(fmt.Sprintf("Failed to list API resources: %s", err))
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, api := range apis {
+		choices = append(choices, api.WithGroupString()+"/")
+	}
+	if len(choices) == 0 {
+		cobra.CompDebugln("No API resources found", false)
+	}
+
+	return choices, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// compGetVerbList provides auto-completion for the verbs that can be granted by
+// the policy rules of a Role or ClusterRole.
+func compGetVerbList(toComplete string) ([]string, cobra.ShellCompDirective) {
+	cobra.CompDebugln(fmt.Sprintf("compGetVerbList with \"%s\"", toComplete), false)
+
+	var choices []string
+	for _, verb := range []string{"create", "delete", "deletecollection", "get", "list", "patch", "update", "watch"} {
+		if strings.HasPrefix(verb, toComplete) {
+			choices = append(choices, verb)
+		}
+	}
+
+	return choices, cobra.ShellCompDirectiveNoFileComp
+}
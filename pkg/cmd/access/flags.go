@@ -0,0 +1,78 @@
+package access
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/tohjustin/kube-lineage/internal/completion"
+)
+
+const (
+	flagAllNamespaces          = "all-namespaces"
+	flagAllNamespacesShorthand = "A"
+	flagNoHeaders              = "no-headers"
+	flagScopes                 = "scopes"
+	flagScopesShorthand        = "S"
+	flagShowGroup              = "show-group"
+)
+
+// Flags composes common configuration flag structs used in the command.
+type Flags struct {
+	AllNamespaces *bool
+	NoHeaders     *bool
+	Scopes        *[]string
+	ShowGroup     *bool
+}
+
+// Copy returns a copy of Flags for mutation.
+func (f *Flags) Copy() Flags {
+	Flags := *f
+	return Flags
+}
+
+// AddFlags receives a *pflag.FlagSet reference and binds flags related to
+// configuration to it.
+func (f *Flags) AddFlags(flags *pflag.FlagSet) {
+	if f.AllNamespaces != nil {
+		flags.BoolVarP(f.AllNamespaces, flagAllNamespaces, flagAllNamespacesShorthand, *f.AllNamespaces, "If present, find bindings & pods across all namespaces")
+	}
+	if f.NoHeaders != nil {
+		flags.BoolVar(f.NoHeaders, flagNoHeaders, *f.NoHeaders, "When using the default output format, don't print headers (default print headers)")
+	}
+	if f.Scopes != nil {
+		usage := fmt.Sprintf("Accepts a comma separated list of additional namespaces to find bindings & pods. You can also use multiple flag options like -%s namespace1 -%s namespace2...", flagScopesShorthand, flagScopesShorthand)
+		flags.StringSliceVarP(f.Scopes, flagScopes, flagScopesShorthand, *f.Scopes, usage)
+	}
+	if f.ShowGroup != nil {
+		flags.BoolVar(f.ShowGroup, flagShowGroup, *f.ShowGroup, "If present, include the resource group for the objects in each grant path")
+	}
+}
+
+// RegisterFlagCompletionFunc receives a *cobra.Command & register functions to
+// to provide completion for flags related to configuration.
+func (*Flags) RegisterFlagCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
+		flagScopes,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetScopeNamespaceList(f, cmd, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}))
+}
+
+// NewFlags returns flags associated with command configuration, with default
+// values set.
+func NewFlags() *Flags {
+	allNamespaces := false
+	noHeaders := false
+	scopes := []string{}
+	showGroup := false
+
+	return &Flags{
+		AllNamespaces: &allNamespaces,
+		NoHeaders:     &noHeaders,
+		Scopes:        &scopes,
+		ShowGroup:     &showGroup,
+	}
+}
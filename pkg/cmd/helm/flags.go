@@ -17,17 +17,19 @@ const (
 	flagDepthShorthand         = "d"
 	flagExcludeTypes           = "exclude-types"
 	flagIncludeTypes           = "include-types"
+	flagRelationshipRules      = "relationship-rules"
 	flagScopes                 = "scopes"
 	flagScopesShorthand        = "S"
 )
 
 // Flags composes common configuration flag structs used in the command.
 type Flags struct {
-	AllNamespaces *bool
-	Depth         *uint
-	ExcludeTypes  *[]string
-	IncludeTypes  *[]string
-	Scopes        *[]string
+	AllNamespaces     *bool
+	Depth             *uint
+	ExcludeTypes      *[]string
+	IncludeTypes      *[]string
+	RelationshipRules *string
+	Scopes            *[]string
 }
 
 // Copy returns a copy of Flags for mutation.
@@ -53,6 +55,9 @@ func (f *Flags) AddFlags(flags *pflag.FlagSet) {
 		usage := fmt.Sprintf("Accepts a comma separated list of resource types to only include in relationship discovery. You can also use multiple flag options like --%s kind1 --%s kind1...", flagIncludeTypes, flagIncludeTypes)
 		flags.StringSliceVar(f.IncludeTypes, flagIncludeTypes, *f.IncludeTypes, usage)
 	}
+	if f.RelationshipRules != nil {
+		flags.StringVar(f.RelationshipRules, flagRelationshipRules, *f.RelationshipRules, "Path to a YAML file containing rules to discover relationships of additional resource types (default \"~/.kube-lineage/rules.yaml\")")
+	}
 	if f.Scopes != nil {
 		usage := fmt.Sprintf("Accepts a comma separated list of additional namespaces to find relationships. You can also use multiple flag options like -%s namespace1 -%s namespace2...", flagScopesShorthand, flagScopesShorthand)
 		flags.StringSliceVarP(f.Scopes, flagScopes, flagScopesShorthand, *f.Scopes, usage)
@@ -76,13 +81,15 @@ func NewFlags() *Flags {
 	depth := uint(0)
 	excludeTypes := []string{}
 	includeTypes := []string{}
+	relationshipRules := ""
 	scopes := []string{}
 
 	return &Flags{
-		AllNamespaces: &allNamespaces,
-		Depth:         &depth,
-		ExcludeTypes:  &excludeTypes,
-		IncludeTypes:  &includeTypes,
-		Scopes:        &scopes,
+		AllNamespaces:     &allNamespaces,
+		Depth:             &depth,
+		ExcludeTypes:      &excludeTypes,
+		IncludeTypes:      &includeTypes,
+		RelationshipRules: &relationshipRules,
+		Scopes:            &scopes,
 	}
 }
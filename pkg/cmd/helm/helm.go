@@ -45,7 +45,10 @@ var (
 		%CMD_PATH% pv/disk --dependencies --exclude-types=ev,secret
 
 		# List only resources provisioned by the release named "bar"
-		%CMD_PATH% bar --depth=1`)
+		%CMD_PATH% bar --depth=1
+
+		# Generate a Mermaid flowchart of all resources associated with release named "bar"
+		%CMD_PATH% bar --output=mermaid`)
 	cmdShort = "Display resources associated with a Helm release & their dependents"
 	cmdLong  = templates.LongDesc(`
 		Display resources associated with a Helm release & their dependents.
@@ -137,6 +140,11 @@ func (o *CmdOptions) Complete(cmd *cobra.Command, args []string) error {
 		o.RequestRelease = args[0]
 	}
 
+	// Register relationship rules
+	if err = graph.LoadRelationshipRules(*o.Flags.RelationshipRules); err != nil {
+		return err
+	}
+
 	// Setup client
 	o.Namespace, _, err = o.ClientFlags.ToRawKubeConfigLoader().Namespace()
 	if err != nil {
@@ -174,6 +182,7 @@ func (o *CmdOptions) Validate() error {
 	klog.V(4).Infof("Flags.Depth: %v", *o.Flags.Depth)
 	klog.V(4).Infof("Flags.ExcludeTypes: %v", *o.Flags.ExcludeTypes)
 	klog.V(4).Infof("Flags.IncludeTypes: %v", *o.Flags.IncludeTypes)
+	klog.V(4).Infof("Flags.RelationshipRules: %s", *o.Flags.RelationshipRules)
 	klog.V(4).Infof("Flags.Scopes: %v", *o.Flags.Scopes)
 	klog.V(4).Infof("ClientFlags.Context: %s", *o.ClientFlags.Context)
 	klog.V(4).Infof("ClientFlags.Namespace: %s", *o.ClientFlags.Namespace)
@@ -182,6 +191,7 @@ func (o *CmdOptions) Validate() error {
 	klog.V(4).Infof("PrintFlags.ShowGroup: %t", *o.PrintFlags.HumanReadableFlags.ShowGroup)
 	klog.V(4).Infof("PrintFlags.ShowLabels: %t", *o.PrintFlags.HumanReadableFlags.ShowLabels)
 	klog.V(4).Infof("PrintFlags.ShowNamespace: %t", *o.PrintFlags.HumanReadableFlags.ShowNamespace)
+	klog.V(4).Infof("PrintFlags.ShowObject: %t", *o.PrintFlags.JSONYamlFlags.ShowObject)
 
 	return nil
 }
@@ -318,7 +328,7 @@ func (o *CmdOptions) Run() error {
 
 	// Find all dependents of the release & storage objects
 	mapper := o.Client.GetMapper()
-	nodeMap, err := graph.ResolveDependents(mapper, objs.Items, uids)
+	nodeMap, err := graph.ResolveDependents(mapper, objs.Items, uids, graph.ResolveOptions{})
 	if err != nil {
 		return err
 	}
@@ -338,7 +348,7 @@ func (o *CmdOptions) Run() error {
 	nodeMap[rootUID] = rootNode
 
 	// Print output
-	return o.Printer.Print(o.Out, nodeMap, rootUID, *o.Flags.Depth, false)
+	return o.Printer.Print(o.Out, nodeMap, []types.UID{rootUID}, *o.Flags.Depth, false)
 }
 
 // getManifestObjects fetches all objects found in the manifest of the provided
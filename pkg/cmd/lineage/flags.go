@@ -18,19 +18,28 @@ const (
 	flagDepth                  = "depth"
 	flagDepthShorthand         = "d"
 	flagExcludeTypes           = "exclude-types"
+	flagFilenames              = "filename"
 	flagIncludeTypes           = "include-types"
+	flagIncludeWildcardRules   = "include-wildcard-policy-rules"
+	flagPolicyRuleVerbs        = "policy-rule-verbs"
 	flagScopes                 = "scopes"
 	flagScopesShorthand        = "S"
+	flagSelector               = "selector"
+	flagSelectorShorthand      = "l"
 )
 
 // Flags composes common configuration flag structs used in the command.
 type Flags struct {
-	AllNamespaces *bool
-	Dependencies  *bool
-	Depth         *uint
-	ExcludeTypes  *[]string
-	IncludeTypes  *[]string
-	Scopes        *[]string
+	AllNamespaces    *bool
+	Dependencies     *bool
+	Depth            *uint
+	ExcludeTypes     *[]string
+	Filenames        *[]string
+	IncludeTypes     *[]string
+	IncludeWildcards *bool
+	PolicyRuleVerbs  *[]string
+	Scopes           *[]string
+	Selector         *string
 }
 
 // Copy returns a copy of Flags for mutation.
@@ -55,14 +64,28 @@ func (f *Flags) AddFlags(flags *pflag.FlagSet) {
 		usage := fmt.Sprintf("Accepts a comma separated list of resource types to exclude from relationship discovery. You can also use multiple flag options like --%s kind1 --%s kind1...", flagExcludeTypes, flagExcludeTypes)
 		flags.StringSliceVar(f.ExcludeTypes, flagExcludeTypes, *f.ExcludeTypes, usage)
 	}
+	if f.Filenames != nil {
+		usage := fmt.Sprintf("Accepts a comma separated list of files, directories or tar archives containing objects to list the relationships of, along with any requested objects. Unlike kubectl, there's no -f shorthand since it's taken by --from-file. You can also use multiple flag options like --%s file1 --%s file2...", flagFilenames, flagFilenames)
+		flags.StringSliceVar(f.Filenames, flagFilenames, *f.Filenames, usage)
+	}
 	if f.IncludeTypes != nil {
 		usage := fmt.Sprintf("Accepts a comma separated list of resource types to only include in relationship discovery. You can also use multiple flag options like --%s kind1 --%s kind1...", flagIncludeTypes, flagIncludeTypes)
 		flags.StringSliceVar(f.IncludeTypes, flagIncludeTypes, *f.IncludeTypes, usage)
 	}
+	if f.IncludeWildcards != nil {
+		flags.BoolVar(f.IncludeWildcards, flagIncludeWildcardRules, *f.IncludeWildcards, "If present, include relationships of policy rules that match all API groups or resources")
+	}
+	if f.PolicyRuleVerbs != nil {
+		usage := fmt.Sprintf("Accepts a comma separated list of verbs to find relationships between role bindings & the objects that the policy rules of their roles allow the verbs to be performed on, or an empty list to skip these relationships. You can also use multiple flag options like --%s verb1 --%s verb2...", flagPolicyRuleVerbs, flagPolicyRuleVerbs)
+		flags.StringSliceVar(f.PolicyRuleVerbs, flagPolicyRuleVerbs, *f.PolicyRuleVerbs, usage)
+	}
 	if f.Scopes != nil {
 		usage := fmt.Sprintf("Accepts a comma separated list of additional namespaces to find relationships. You can also use multiple flag options like -%s namespace1 -%s namespace2...", flagScopesShorthand, flagScopesShorthand)
 		flags.StringSliceVarP(f.Scopes, flagScopes, flagScopesShorthand, *f.Scopes, usage)
 	}
+	if f.Selector != nil {
+		flags.StringVarP(f.Selector, flagSelector, flagSelectorShorthand, *f.Selector, "Selector (label query) to filter objects of the requested resource type, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
+	}
 }
 
 // RegisterFlagCompletionFunc receives a *cobra.Command & register functions to
@@ -82,15 +105,23 @@ func NewFlags() *Flags {
 	dependencies := false
 	depth := uint(0)
 	excludeTypes := []string{}
+	filenames := []string{}
 	includeTypes := []string{}
+	includeWildcards := false
+	policyRuleVerbs := []string{"get", "list", "watch"}
 	scopes := []string{}
+	selector := ""
 
 	return &Flags{
-		AllNamespaces: &allNamespaces,
-		Dependencies:  &dependencies,
-		Depth:         &depth,
-		ExcludeTypes:  &excludeTypes,
-		IncludeTypes:  &includeTypes,
-		Scopes:        &scopes,
+		AllNamespaces:    &allNamespaces,
+		Dependencies:     &dependencies,
+		Depth:            &depth,
+		ExcludeTypes:     &excludeTypes,
+		Filenames:        &filenames,
+		IncludeTypes:     &includeTypes,
+		IncludeWildcards: &includeWildcards,
+		PolicyRuleVerbs:  &policyRuleVerbs,
+		Scopes:           &scopes,
+		Selector:         &selector,
 	}
 }
@@ -6,6 +6,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/klog/v2"
@@ -18,16 +21,26 @@ import (
 	"github.com/tohjustin/kube-lineage/internal/graph"
 	"github.com/tohjustin/kube-lineage/internal/log"
 	lineageprinters "github.com/tohjustin/kube-lineage/internal/printers"
+	lineageutil "github.com/tohjustin/kube-lineage/pkg/cmd/util"
 )
 
 var (
 	cmdPath    string
 	cmdName    = "lineage"
-	cmdUse     = "%CMD% (TYPE[.VERSION][.GROUP] [NAME] | TYPE[.VERSION][.GROUP]/NAME) [flags]"
+	cmdUse     = "%CMD% (TYPE[.VERSION][.GROUP] [NAME ...] | TYPE[.VERSION][.GROUP]/NAME ...) [flags]"
 	cmdExample = templates.Examples(`
 		# List all dependents of the deployment named "bar" in the current namespace
 		%CMD_PATH% deployments bar
 
+		# List all dependents of the deployments named "bar" & "baz" & the service named "qux"
+		%CMD_PATH% deploy/bar deploy/baz svc/qux
+
+		# List all dependents of the deployments with the label "app=bar"
+		%CMD_PATH% deployments -l app=bar
+
+		# List all dependents of the objects in the manifest "bar.yaml"
+		%CMD_PATH% --filename=bar.yaml
+
 		# List all dependents of the cronjob named "bar" in namespace "foo"
 		%CMD_PATH% cronjobs.batch/bar --namespace=foo
 
@@ -41,10 +54,37 @@ var (
 		%CMD_PATH% pod.v1. bar-5cc79d4bf5-xgvkc --dependencies
 
 		# List all dependencies of the serviceaccount named "default" in the current namespace, grouped by resource type
-		%CMD_PATH% sa/default --dependencies --output=split`)
-	cmdShort = "Display all dependencies or dependents of a Kubernetes object"
+		%CMD_PATH% sa/default --dependencies --output=split
+
+		# List all dependents of the secret named "bar", including the roles whose policy rules allow getting it
+		%CMD_PATH% secret/bar --policy-rule-verbs=get
+
+		# List all dependents of the deployment named "bar" as JSON, including the manifest of each object
+		%CMD_PATH% deploy/bar --output=json --show-object
+
+		# Render all dependents of the secret named "bar" as a graph using Graphviz
+		%CMD_PATH% secret/bar --output=dot | dot -Tsvg > bar.svg
+
+		# Generate a Mermaid flowchart of all dependencies of the pod named "bar"
+		%CMD_PATH% pod/bar --dependencies --output=mermaid
+
+		# List all dependents of the deployment named "bar" using objects from a cluster dump, without access to the cluster
+		%CMD_PATH% deploy/bar --from-file=cluster-dump.tar.gz
+
+		# Generate an interactive HTML report of all dependents of the node named "bar"
+		%CMD_PATH% node/bar --output=html > bar.html`)
+	cmdShort = "Display all dependencies or dependents of Kubernetes objects"
 	cmdLong  = templates.LongDesc(`
-		Display all dependencies or dependents of a Kubernetes object.
+		Display all dependencies or dependents of Kubernetes objects.
+
+		Objects can be requested by their names, by a label selector of their
+		type or by manifests containing them. When multiple objects are requested,
+		objects shared by them only have their dependencies or dependents listed
+		once.
+
+		Manifests are provided with --filename, which has no -f shorthand unlike
+		kubectl since -f is the shorthand of --from-file, which reads every object
+		from manifests instead of the cluster.
 
 		TYPE is a Kubernetes resource. Shortcuts and groups will be resolved.
 		NAME is the name of a particular Kubernetes resource.`)
@@ -52,15 +92,18 @@ var (
 
 // CmdOptions contains all the options for running the lineage command.
 type CmdOptions struct {
-	// RequestType represents the type of the requested object.
+	// RequestType represents the type of the requested objects, when they're
+	// requested by a label selector.
 	RequestType string
-	// RequestName represents the name of the requested object.
-	RequestName string
-	Flags       *Flags
+	// RequestObjects represents the requested objects, in the <resource>/<name>
+	// form.
+	RequestObjects []string
+	Flags          *Flags
 
 	Namespace   string
 	Client      client.Interface
 	ClientFlags *client.Flags
+	GraphFlags  *lineageutil.GraphFlags
 
 	Printer    lineageprinters.Interface
 	PrintFlags *lineageprinters.Flags
@@ -73,6 +116,7 @@ func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *co
 	o := &CmdOptions{
 		Flags:       NewFlags(),
 		ClientFlags: client.NewFlags(),
+		GraphFlags:  lineageutil.NewGraphFlags(),
 		PrintFlags:  lineageprinters.NewFlags(),
 		IOStreams:   streams,
 	}
@@ -92,7 +136,7 @@ func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *co
 		Example:               strings.ReplaceAll(cmdExample, "%CMD_PATH%", cmdPath),
 		Short:                 cmdShort,
 		Long:                  cmdLong,
-		Args:                  cobra.MaximumNArgs(2),
+		Args:                  cobra.ArbitraryArgs,
 		DisableFlagsInUseLine: true,
 		DisableSuggestions:    true,
 		SilenceUsage:          true,
@@ -104,10 +148,10 @@ func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *co
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			var comps []string
-			switch len(args) {
-			case 0:
+			switch {
+			case len(args) == 0:
 				comps = compGetResourceList(o, toComplete)
-			case 1:
+			case !strings.Contains(args[0], "/"):
 				comps = get.CompGetResource(f, cmd, args[0], toComplete)
 			}
 			return comps, cobra.ShellCompDirectiveNoFileComp
@@ -117,6 +161,7 @@ func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *co
 	// Setup flags
 	o.Flags.AddFlags(cmd.Flags())
 	o.ClientFlags.AddFlags(cmd.Flags())
+	o.GraphFlags.AddFlags(cmd.Flags())
 	o.PrintFlags.AddFlags(cmd.Flags())
 	log.AddFlags(cmd.Flags())
 
@@ -131,25 +176,22 @@ func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *co
 func (o *CmdOptions) Complete(cmd *cobra.Command, args []string) error {
 	var err error
 
-	switch len(args) {
-	case 1:
-		resourceTokens := strings.SplitN(args[0], "/", 2)
-		if len(resourceTokens) != 2 {
-			return fmt.Errorf("arguments in <resource>/<name> form must have a single resource and name\nSee '%s -h' for help and examples", cmdPath)
-		}
-		o.RequestType = resourceTokens[0]
-		o.RequestName = resourceTokens[1]
-	case 2:
+	// Objects are either requested as <resource>/<name> ... or as
+	// <resource> <name> ..., similar to "kubectl get"
+	o.RequestType, o.RequestObjects = "", []string{}
+	switch {
+	case len(args) == 0:
+	case strings.Contains(args[0], "/"):
+		o.RequestObjects = args
+	default:
 		o.RequestType = args[0]
-		o.RequestName = args[1]
+		for _, name := range args[1:] {
+			o.RequestObjects = append(o.RequestObjects, o.RequestType+"/"+name)
+		}
 	}
 
-	// Setup client
-	o.Namespace, _, err = o.ClientFlags.ToRawKubeConfigLoader().Namespace()
-	if err != nil {
-		return err
-	}
-	o.Client, err = o.ClientFlags.ToClient()
+	// Register relationship rules & setup client
+	o.Client, o.Namespace, err = o.GraphFlags.Complete(o.ClientFlags)
 	if err != nil {
 		return err
 	}
@@ -165,115 +207,200 @@ func (o *CmdOptions) Complete(cmd *cobra.Command, args []string) error {
 
 // Validate validates all the required options for the lineage command.
 func (o *CmdOptions) Validate() error {
-	if len(o.RequestType) == 0 || len(o.RequestName) == 0 {
+	hasSelector := o.Flags.Selector != nil && len(*o.Flags.Selector) > 0
+	hasFilenames := o.Flags.Filenames != nil && len(*o.Flags.Filenames) > 0
+	switch {
+	case hasSelector && len(o.RequestType) == 0:
+		return fmt.Errorf("resource type must be specified as <resource> when using a selector\nSee '%s -h' for help and examples", cmdPath)
+	case hasSelector && len(o.RequestObjects) > 0:
+		return fmt.Errorf("names cannot be provided when a selector is specified\nSee '%s -h' for help and examples", cmdPath)
+	case !hasSelector && !hasFilenames && len(o.RequestObjects) == 0:
 		return fmt.Errorf("resource must be specified as <resource> <name> or <resource>/<name>\nSee '%s -h' for help and examples", cmdPath)
 	}
+	for _, r := range o.RequestObjects {
+		if tokens := strings.SplitN(r, "/", 2); len(tokens) != 2 || len(tokens[0]) == 0 || len(tokens[1]) == 0 {
+			return fmt.Errorf("arguments in <resource>/<name> form must have a single resource and name\nSee '%s -h' for help and examples", cmdPath)
+		}
+	}
+	if hasSelector {
+		if _, err := labels.Parse(*o.Flags.Selector); err != nil {
+			return err
+		}
+	}
 
 	klog.V(4).Infof("Namespace: %s", o.Namespace)
 	klog.V(4).Infof("RequestType: %v", o.RequestType)
-	klog.V(4).Infof("RequestName: %v", o.RequestName)
+	klog.V(4).Infof("RequestObjects: %v", o.RequestObjects)
 	klog.V(4).Infof("Flags.AllNamespaces: %t", *o.Flags.AllNamespaces)
 	klog.V(4).Infof("Flags.Dependencies: %t", *o.Flags.Dependencies)
 	klog.V(4).Infof("Flags.Depth: %v", *o.Flags.Depth)
 	klog.V(4).Infof("Flags.ExcludeTypes: %v", *o.Flags.ExcludeTypes)
+	klog.V(4).Infof("Flags.Filenames: %v", *o.Flags.Filenames)
 	klog.V(4).Infof("Flags.IncludeTypes: %v", *o.Flags.IncludeTypes)
+	klog.V(4).Infof("Flags.IncludeWildcards: %t", *o.Flags.IncludeWildcards)
+	klog.V(4).Infof("Flags.PolicyRuleVerbs: %v", *o.Flags.PolicyRuleVerbs)
 	klog.V(4).Infof("Flags.Scopes: %v", *o.Flags.Scopes)
+	klog.V(4).Infof("Flags.Selector: %s", *o.Flags.Selector)
 	klog.V(4).Infof("ClientFlags.Context: %s", *o.ClientFlags.Context)
 	klog.V(4).Infof("ClientFlags.Namespace: %s", *o.ClientFlags.Namespace)
+	klog.V(4).Infof("GraphFlags.FromFiles: %v", *o.GraphFlags.FromFiles)
+	klog.V(4).Infof("GraphFlags.RelationshipRules: %s", *o.GraphFlags.RelationshipRules)
 	klog.V(4).Infof("PrintFlags.OutputFormat: %s", *o.PrintFlags.OutputFormat)
 	klog.V(4).Infof("PrintFlags.NoHeaders: %t", *o.PrintFlags.HumanReadableFlags.NoHeaders)
 	klog.V(4).Infof("PrintFlags.ShowGroup: %t", *o.PrintFlags.HumanReadableFlags.ShowGroup)
 	klog.V(4).Infof("PrintFlags.ShowLabels: %t", *o.PrintFlags.HumanReadableFlags.ShowLabels)
 	klog.V(4).Infof("PrintFlags.ShowNamespace: %t", *o.PrintFlags.HumanReadableFlags.ShowNamespace)
+	klog.V(4).Infof("PrintFlags.ShowObject: %t", *o.PrintFlags.JSONYamlFlags.ShowObject)
 
 	return nil
 }
 
 // Run implements all the necessary functionality for the lineage command.
-//nolint:funlen
 func (o *CmdOptions) Run() error {
 	ctx := context.Background()
 
-	// First check if Kubernetes cluster is reachable
+	// First check if Kubernetes cluster is reachable (always reachable when
+	// reading objects from files)
 	if err := o.Client.IsReachable(); err != nil {
 		return err
 	}
 
-	// Fetch the provided object to ensure it exists before proceeding
-	api, err := o.Client.ResolveAPIResource(o.RequestType)
-	if err != nil {
-		return err
-	}
-	obj := client.ObjectMeta{
-		APIResource: *api,
-		Name:        o.RequestName,
-		Namespace:   o.Namespace,
+	// Determine the objects to list
+	listOpts := lineageutil.ListOptions{
+		Namespace:     o.Namespace,
+		AllNamespaces: *o.Flags.AllNamespaces,
+		Scopes:        *o.Flags.Scopes,
+		ExcludeTypes:  *o.Flags.ExcludeTypes,
+		IncludeTypes:  *o.Flags.IncludeTypes,
 	}
-	root, err := o.Client.Get(ctx, obj.Name, client.GetOptions{
-		APIResource: obj.APIResource,
-		Namespace:   o.Namespace,
-	})
+
+	// Fetch the requested objects to ensure they exist before proceeding
+	roots, err := o.getRootObjects(ctx, listOpts.Namespaces())
 	if err != nil {
 		return err
 	}
 
-	// Determine resources to list
-	excludeAPIs := []client.APIResource{}
-	if o.Flags.ExcludeTypes != nil {
-		for _, kind := range *o.Flags.ExcludeTypes {
-			api, err := o.Client.ResolveAPIResource(kind)
-			if err != nil {
-				return err
-			}
-			excludeAPIs = append(excludeAPIs, *api)
-		}
-	}
-	includeAPIs := []client.APIResource{}
-	if o.Flags.IncludeTypes != nil {
-		for _, kind := range *o.Flags.IncludeTypes {
-			api, err := o.Client.ResolveAPIResource(kind)
-			if err != nil {
-				return err
-			}
-			includeAPIs = append(includeAPIs, *api)
-		}
-	}
-
-	// Determine the namespaces to list objects
-	namespaces := []string{o.Namespace}
-	if o.Flags.AllNamespaces != nil && *o.Flags.AllNamespaces {
-		namespaces = append(namespaces, "")
-	}
-	if o.Flags.Scopes != nil {
-		namespaces = append(namespaces, *o.Flags.Scopes...)
-	}
-
 	// Fetch resources in the cluster
-	objs, err := o.Client.List(ctx, client.ListOptions{
-		APIResourcesToExclude: excludeAPIs,
-		APIResourcesToInclude: includeAPIs,
-		Namespaces:            namespaces,
-	})
+	objs, _, err := lineageutil.ListObjects(ctx, o.Client, listOpts)
 	if err != nil {
 		return err
 	}
 
-	// Include root object into objects to handle cases where user has access
-	// to get the root object but unable to list its resource type
-	objs.Items = append(objs.Items, *root)
+	// Include root objects into objects to handle cases where user has access
+	// to get the root objects but unable to list their resource type
+	objs = append(objs, roots...)
 
-	// Find either all dependencies or dependents of the root object
+	// Find either all dependencies or dependents of the root objects
 	depsIsDependencies, resolveDeps := false, graph.ResolveDependents
 	if o.Flags.Dependencies != nil && *o.Flags.Dependencies {
 		depsIsDependencies, resolveDeps = true, graph.ResolveDependencies
 	}
 	mapper := o.Client.GetMapper()
-	rootUID := root.GetUID()
-	nodeMap, err := resolveDeps(mapper, objs.Items, []types.UID{rootUID})
+	rootUIDs := make([]types.UID, len(roots))
+	for ix, root := range roots {
+		rootUIDs[ix] = root.GetUID()
+	}
+	nodeMap, err := resolveDeps(mapper, objs, rootUIDs, graph.ResolveOptions{
+		PolicyRuleVerbs:            *o.Flags.PolicyRuleVerbs,
+		IncludeWildcardPolicyRules: *o.Flags.IncludeWildcards,
+	})
 	if err != nil {
 		return err
 	}
 
 	// Print output
-	return o.Printer.Print(o.Out, nodeMap, rootUID, *o.Flags.Depth, depsIsDependencies)
+	return o.Printer.Print(o.Out, nodeMap, rootUIDs, *o.Flags.Depth, depsIsDependencies)
+}
+
+// getRootObjects fetches the requested objects, which are either requested by
+// their names, by a label selector of their resource type or by manifests
+// containing them. Objects requested by a label selector are listed in the
+// provided namespaces.
+//
+//nolint:funlen,gocognit
+func (o *CmdOptions) getRootObjects(ctx context.Context, namespaces []string) ([]unstructuredv1.Unstructured, error) {
+	var result []unstructuredv1.Unstructured
+
+	// Objects requested by their names
+	for _, r := range o.RequestObjects {
+		tokens := strings.SplitN(r, "/", 2)
+		api, err := o.Client.ResolveAPIResource(tokens[0])
+		if err != nil {
+			return nil, err
+		}
+		obj, err := o.Client.Get(ctx, tokens[1], client.GetOptions{
+			APIResource: *api,
+			Namespace:   o.Namespace,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *obj)
+	}
+
+	// Objects requested by a label selector
+	if o.Flags.Selector != nil && len(*o.Flags.Selector) > 0 {
+		selector, err := labels.Parse(*o.Flags.Selector)
+		if err != nil {
+			return nil, err
+		}
+		api, err := o.Client.ResolveAPIResource(o.RequestType)
+		if err != nil {
+			return nil, err
+		}
+		objs, err := o.Client.List(ctx, client.ListOptions{
+			APIResourcesToInclude: []client.APIResource{*api},
+			Namespaces:            namespaces,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var matched []unstructuredv1.Unstructured
+		for _, obj := range objs.Items {
+			if obj.GroupVersionKind().GroupKind() == api.GroupKind() && selector.Matches(labels.Set(obj.GetLabels())) {
+				matched = append(matched, obj)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("no %s found matching selector \"%s\"", api.WithGroupString(), selector)
+		}
+		result = append(result, matched...)
+	}
+
+	// Objects requested by manifests, objects without a namespace are fetched
+	// from the current namespace
+	if o.Flags.Filenames != nil && len(*o.Flags.Filenames) > 0 {
+		manifests, err := client.ReadObjectsFromFiles(*o.Flags.Filenames)
+		if err != nil {
+			return nil, err
+		}
+		mapper := o.Client.GetMapper()
+		for _, manifest := range manifests {
+			gvk := manifest.GroupVersionKind()
+			m, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if err != nil {
+				return nil, err
+			}
+			ns := manifest.GetNamespace()
+			if len(ns) == 0 {
+				ns = o.Namespace
+			}
+			obj, err := o.Client.Get(ctx, manifest.GetName(), client.GetOptions{
+				APIResource: client.APIResource{
+					Name:       m.Resource.Resource,
+					Namespaced: m.Scope.Name() == meta.RESTScopeNameNamespace,
+					Group:      m.GroupVersionKind.Group,
+					Version:    m.GroupVersionKind.Version,
+					Kind:       m.GroupVersionKind.Kind,
+				},
+				Namespace: ns,
+			})
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, *obj)
+		}
+	}
+
+	return result, nil
 }
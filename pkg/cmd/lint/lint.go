@@ -0,0 +1,227 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/tohjustin/kube-lineage/internal/client"
+	"github.com/tohjustin/kube-lineage/internal/graph"
+	"github.com/tohjustin/kube-lineage/internal/log"
+	lineageprinters "github.com/tohjustin/kube-lineage/internal/printers"
+	lineageutil "github.com/tohjustin/kube-lineage/pkg/cmd/util"
+)
+
+var (
+	cmdPath    string
+	cmdName    = "lint"
+	cmdUse     = "%CMD% [flags]"
+	cmdExample = templates.Examples(`
+		# List all references to missing objects in the current namespace
+		%CMD_PATH%
+
+		# List all references to missing objects in namespace "foo"
+		%CMD_PATH% --namespace=foo
+
+		# List all references to missing objects across all namespaces, excluding event resource types
+		%CMD_PATH% --all-namespaces --exclude-types=ev
+
+		# List all references to missing objects using objects from a cluster dump
+		%CMD_PATH% --all-namespaces --from-file=cluster-dump.tar.gz`)
+	cmdShort = "Display all references to Kubernetes objects that don't exist"
+	cmdLong  = templates.LongDesc(`
+		Display all references to Kubernetes objects that don't exist.
+
+		Each reference is printed along with the relationship types that produced
+		it, for example a pod mounting a configmap that doesn't exist or an ingress
+		routing traffic to a service that was deleted.
+
+		Only references to objects within the namespaces being linted are reported,
+		references to cluster-scoped objects are only reported when linting across
+		all namespaces. References to objects that are allowed to not exist (e.g.
+		configmaps & secrets referenced with "optional: true") aren't reported.`)
+)
+
+// CmdOptions contains all the options for running the lint command.
+type CmdOptions struct {
+	Flags *Flags
+
+	Namespace   string
+	Client      client.Interface
+	ClientFlags *client.Flags
+	GraphFlags  *lineageutil.GraphFlags
+
+	genericclioptions.IOStreams
+}
+
+// NewCmd returns an initialized Command for the lint command.
+func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *cobra.Command {
+	o := &CmdOptions{
+		Flags:       NewFlags(),
+		ClientFlags: client.NewFlags(),
+		GraphFlags:  lineageutil.NewGraphFlags(),
+		IOStreams:   streams,
+	}
+
+	f := cmdutil.NewFactory(o.ClientFlags)
+	util.SetFactoryForCompletion(f)
+
+	if len(name) > 0 {
+		cmdName = name
+	}
+	cmdPath = cmdName
+	if len(parentCmdPath) > 0 {
+		cmdPath = parentCmdPath + " " + cmdName
+	}
+	cmd := &cobra.Command{
+		Use:                   strings.ReplaceAll(cmdUse, "%CMD%", cmdName),
+		Example:               strings.ReplaceAll(cmdExample, "%CMD_PATH%", cmdPath),
+		Short:                 cmdShort,
+		Long:                  cmdLong,
+		Args:                  cobra.NoArgs,
+		DisableFlagsInUseLine: true,
+		DisableSuggestions:    true,
+		SilenceUsage:          true,
+		Run: func(c *cobra.Command, args []string) {
+			klog.V(4).Infof("Version: %s", c.Root().Version)
+			cmdutil.CheckErr(o.Complete(c, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	// Setup flags
+	o.Flags.AddFlags(cmd.Flags())
+	o.ClientFlags.AddFlags(cmd.Flags())
+	o.GraphFlags.AddFlags(cmd.Flags())
+	log.AddFlags(cmd.Flags())
+
+	// Setup flag completion function
+	o.Flags.RegisterFlagCompletionFunc(cmd, f)
+	o.ClientFlags.RegisterFlagCompletionFunc(cmd, f)
+
+	return cmd
+}
+
+// Complete completes all the required options for the lint command.
+func (o *CmdOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	// Register relationship rules & setup client
+	o.Client, o.Namespace, err = o.GraphFlags.Complete(o.ClientFlags)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates all the required options for the lint command.
+func (o *CmdOptions) Validate() error {
+	klog.V(4).Infof("Namespace: %s", o.Namespace)
+	klog.V(4).Infof("Flags.AllNamespaces: %t", *o.Flags.AllNamespaces)
+	klog.V(4).Infof("Flags.ExcludeTypes: %v", *o.Flags.ExcludeTypes)
+	klog.V(4).Infof("Flags.IncludeTypes: %v", *o.Flags.IncludeTypes)
+	klog.V(4).Infof("Flags.NoHeaders: %t", *o.Flags.NoHeaders)
+	klog.V(4).Infof("Flags.Scopes: %v", *o.Flags.Scopes)
+	klog.V(4).Infof("Flags.ShowGroup: %t", *o.Flags.ShowGroup)
+	klog.V(4).Infof("ClientFlags.Context: %s", *o.ClientFlags.Context)
+	klog.V(4).Infof("ClientFlags.Namespace: %s", *o.ClientFlags.Namespace)
+	klog.V(4).Infof("GraphFlags.FromFiles: %v", *o.GraphFlags.FromFiles)
+	klog.V(4).Infof("GraphFlags.RelationshipRules: %s", *o.GraphFlags.RelationshipRules)
+
+	return nil
+}
+
+// Run implements all the necessary functionality for the lint command.
+//
+//nolint:funlen,gocognit
+func (o *CmdOptions) Run() error {
+	ctx := context.Background()
+
+	// First check if Kubernetes cluster is reachable
+	if err := o.Client.IsReachable(); err != nil {
+		return err
+	}
+
+	// Fetch resources in the cluster
+	isClusterScopeRequest := o.Flags.AllNamespaces != nil && *o.Flags.AllNamespaces
+	objs, listOpts, err := lineageutil.ListObjects(ctx, o.Client, lineageutil.ListOptions{
+		Namespace:     o.Namespace,
+		AllNamespaces: isClusterScopeRequest,
+		Scopes:        *o.Flags.Scopes,
+		ExcludeTypes:  *o.Flags.ExcludeTypes,
+		IncludeTypes:  *o.Flags.IncludeTypes,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Resolve the relationships between all objects, including references to
+	// objects that don't exist
+	nodeMap, err := graph.ResolveGraph(o.Client.GetMapper(), objs, graph.ResolveOptions{IncludeMissing: true})
+	if err != nil {
+		return err
+	}
+
+	// Only report missing objects that would've been listed if they existed,
+	// since objects outside of the listed namespaces or resource types are
+	// expected to be missing from the relationship graph
+	nsSet := map[string]struct{}{}
+	for _, ns := range listOpts.Namespaces {
+		nsSet[ns] = struct{}{}
+	}
+	includeGKSet := client.ResourcesToGroupKindSet(listOpts.APIResourcesToInclude)
+	excludeGKSet := client.ResourcesToGroupKindSet(listOpts.APIResourcesToExclude)
+	isListedFn := func(node *graph.Node) bool {
+		gk := schema.GroupKind{Group: node.Group, Kind: node.Kind}
+		if _, ok := includeGKSet[gk]; len(includeGKSet) > 0 && !ok {
+			return false
+		}
+		if _, ok := excludeGKSet[gk]; ok {
+			return false
+		}
+		if isClusterScopeRequest {
+			return true
+		}
+		_, ok := nsSet[node.Namespace]
+		return len(node.Namespace) > 0 && ok
+	}
+	count, referrerNSSet := 0, map[string]struct{}{}
+	for uid, node := range nodeMap {
+		if !node.Missing {
+			continue
+		}
+		if !isListedFn(node) {
+			delete(nodeMap, uid)
+			continue
+		}
+		count++
+		for depUID := range node.Dependents {
+			referrerNSSet[nodeMap[depUID].Namespace] = struct{}{}
+		}
+		for depUID := range node.Dependencies {
+			referrerNSSet[nodeMap[depUID].Namespace] = struct{}{}
+		}
+	}
+	if count == 0 {
+		if isClusterScopeRequest {
+			fmt.Fprintln(o.ErrOut, "No missing references found.")
+		} else {
+			fmt.Fprintf(o.ErrOut, "No missing references found in %s namespace.\n", o.Namespace)
+		}
+		return nil
+	}
+
+	// Print output
+	showNamespace := len(referrerNSSet) > 1 || isClusterScopeRequest
+	return lineageprinters.PrintMissingReferences(o.Out, nodeMap, *o.Flags.ShowGroup, showNamespace, *o.Flags.NoHeaders)
+}
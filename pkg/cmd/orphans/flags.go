@@ -0,0 +1,94 @@
+package orphans
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/tohjustin/kube-lineage/internal/completion"
+)
+
+const (
+	flagAllNamespaces          = "all-namespaces"
+	flagAllNamespacesShorthand = "A"
+	flagExcludeTypes           = "exclude-types"
+	flagIncludeTypes           = "include-types"
+	flagNoHeaders              = "no-headers"
+	flagScopes                 = "scopes"
+	flagScopesShorthand        = "S"
+	flagShowGroup              = "show-group"
+)
+
+// Flags composes common configuration flag structs used in the command.
+type Flags struct {
+	AllNamespaces *bool
+	ExcludeTypes  *[]string
+	IncludeTypes  *[]string
+	NoHeaders     *bool
+	Scopes        *[]string
+	ShowGroup     *bool
+}
+
+// Copy returns a copy of Flags for mutation.
+func (f *Flags) Copy() Flags {
+	Flags := *f
+	return Flags
+}
+
+// AddFlags receives a *pflag.FlagSet reference and binds flags related to
+// configuration to it.
+func (f *Flags) AddFlags(flags *pflag.FlagSet) {
+	if f.AllNamespaces != nil {
+		flags.BoolVarP(f.AllNamespaces, flagAllNamespaces, flagAllNamespacesShorthand, *f.AllNamespaces, "If present, find orphaned objects across all namespaces")
+	}
+	if f.ExcludeTypes != nil {
+		usage := fmt.Sprintf("Accepts a comma separated list of resource types to exclude from relationship discovery. You can also use multiple flag options like --%s kind1 --%s kind1...", flagExcludeTypes, flagExcludeTypes)
+		flags.StringSliceVar(f.ExcludeTypes, flagExcludeTypes, *f.ExcludeTypes, usage)
+	}
+	if f.IncludeTypes != nil {
+		usage := fmt.Sprintf("Accepts a comma separated list of resource types to only include in relationship discovery. You can also use multiple flag options like --%s kind1 --%s kind1...", flagIncludeTypes, flagIncludeTypes)
+		flags.StringSliceVar(f.IncludeTypes, flagIncludeTypes, *f.IncludeTypes, usage)
+	}
+	if f.NoHeaders != nil {
+		flags.BoolVar(f.NoHeaders, flagNoHeaders, *f.NoHeaders, "When using the default output format, don't print headers (default print headers)")
+	}
+	if f.Scopes != nil {
+		usage := fmt.Sprintf("Accepts a comma separated list of additional namespaces to find orphaned objects. You can also use multiple flag options like -%s namespace1 -%s namespace2...", flagScopesShorthand, flagScopesShorthand)
+		flags.StringSliceVarP(f.Scopes, flagScopes, flagScopesShorthand, *f.Scopes, usage)
+	}
+	if f.ShowGroup != nil {
+		flags.BoolVar(f.ShowGroup, flagShowGroup, *f.ShowGroup, "If present, include the resource group for the orphaned objects")
+	}
+}
+
+// RegisterFlagCompletionFunc receives a *cobra.Command & register functions to
+// to provide completion for flags related to configuration.
+func (*Flags) RegisterFlagCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
+		flagScopes,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GetScopeNamespaceList(f, cmd, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}))
+}
+
+// NewFlags returns flags associated with command configuration, with default
+// values set.
+func NewFlags() *Flags {
+	allNamespaces := false
+	excludeTypes := []string{}
+	includeTypes := []string{}
+	noHeaders := false
+	scopes := []string{}
+	showGroup := false
+
+	return &Flags{
+		AllNamespaces: &allNamespaces,
+		ExcludeTypes:  &excludeTypes,
+		IncludeTypes:  &includeTypes,
+		NoHeaders:     &noHeaders,
+		Scopes:        &scopes,
+		ShowGroup:     &showGroup,
+	}
+}
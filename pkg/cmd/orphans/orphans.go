@@ -0,0 +1,189 @@
+package orphans
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/tohjustin/kube-lineage/internal/client"
+	"github.com/tohjustin/kube-lineage/internal/graph"
+	"github.com/tohjustin/kube-lineage/internal/log"
+	lineageprinters "github.com/tohjustin/kube-lineage/internal/printers"
+	lineageutil "github.com/tohjustin/kube-lineage/pkg/cmd/util"
+)
+
+var (
+	cmdPath    string
+	cmdName    = "orphans"
+	cmdUse     = "%CMD% [flags]"
+	cmdExample = templates.Examples(`
+		# List all orphaned objects in the current namespace
+		%CMD_PATH%
+
+		# List all orphaned configmaps, secrets & persistentvolumeclaims in namespace "foo"
+		%CMD_PATH% --namespace=foo --include-types=cm,secret,pvc
+
+		# List all orphaned objects across all namespaces, excluding event resource types
+		%CMD_PATH% --all-namespaces --exclude-types=ev
+
+		# List all orphaned objects using objects from a cluster dump
+		%CMD_PATH% --all-namespaces --from-file=cluster-dump.tar.gz`)
+	cmdShort = "Display all Kubernetes objects without any relationships"
+	cmdLong  = templates.LongDesc(`
+		Display all Kubernetes objects without any relationships.
+
+		An object is orphaned if it has neither dependencies nor dependents, for
+		example a configmap or secret that isn't used by any pod, or a service that
+		isn't exposed by any ingress.
+
+		Only relationships with objects in the namespaces being searched are
+		considered, relationships with cluster-scoped objects are only considered
+		when searching across all namespaces.`)
+)
+
+// CmdOptions contains all the options for running the orphans command.
+type CmdOptions struct {
+	Flags *Flags
+
+	Namespace   string
+	Client      client.Interface
+	ClientFlags *client.Flags
+	GraphFlags  *lineageutil.GraphFlags
+
+	genericclioptions.IOStreams
+}
+
+// NewCmd returns an initialized Command for the orphans command.
+func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *cobra.Command {
+	o := &CmdOptions{
+		Flags:       NewFlags(),
+		ClientFlags: client.NewFlags(),
+		GraphFlags:  lineageutil.NewGraphFlags(),
+		IOStreams:   streams,
+	}
+
+	f := cmdutil.NewFactory(o.ClientFlags)
+	util.SetFactoryForCompletion(f)
+
+	if len(name) > 0 {
+		cmdName = name
+	}
+	cmdPath = cmdName
+	if len(parentCmdPath) > 0 {
+		cmdPath = parentCmdPath + " " + cmdName
+	}
+	cmd := &cobra.Command{
+		Use:                   strings.ReplaceAll(cmdUse, "%CMD%", cmdName),
+		Example:               strings.ReplaceAll(cmdExample, "%CMD_PATH%", cmdPath),
+		Short:                 cmdShort,
+		Long:                  cmdLong,
+		Args:                  cobra.NoArgs,
+		DisableFlagsInUseLine: true,
+		DisableSuggestions:    true,
+		SilenceUsage:          true,
+		Run: func(c *cobra.Command, args []string) {
+			klog.V(4).Infof("Version: %s", c.Root().Version)
+			cmdutil.CheckErr(o.Complete(c, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	// Setup flags
+	o.Flags.AddFlags(cmd.Flags())
+	o.ClientFlags.AddFlags(cmd.Flags())
+	o.GraphFlags.AddFlags(cmd.Flags())
+	log.AddFlags(cmd.Flags())
+
+	// Setup flag completion function
+	o.Flags.RegisterFlagCompletionFunc(cmd, f)
+	o.ClientFlags.RegisterFlagCompletionFunc(cmd, f)
+
+	return cmd
+}
+
+// Complete completes all the required options for the orphans command.
+func (o *CmdOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	// Register relationship rules & setup client
+	o.Client, o.Namespace, err = o.GraphFlags.Complete(o.ClientFlags)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates all the required options for the orphans command.
+func (o *CmdOptions) Validate() error {
+	klog.V(4).Infof("Namespace: %s", o.Namespace)
+	klog.V(4).Infof("Flags.AllNamespaces: %t", *o.Flags.AllNamespaces)
+	klog.V(4).Infof("Flags.ExcludeTypes: %v", *o.Flags.ExcludeTypes)
+	klog.V(4).Infof("Flags.IncludeTypes: %v", *o.Flags.IncludeTypes)
+	klog.V(4).Infof("Flags.NoHeaders: %t", *o.Flags.NoHeaders)
+	klog.V(4).Infof("Flags.Scopes: %v", *o.Flags.Scopes)
+	klog.V(4).Infof("Flags.ShowGroup: %t", *o.Flags.ShowGroup)
+	klog.V(4).Infof("ClientFlags.Context: %s", *o.ClientFlags.Context)
+	klog.V(4).Infof("ClientFlags.Namespace: %s", *o.ClientFlags.Namespace)
+	klog.V(4).Infof("GraphFlags.FromFiles: %v", *o.GraphFlags.FromFiles)
+	klog.V(4).Infof("GraphFlags.RelationshipRules: %s", *o.GraphFlags.RelationshipRules)
+
+	return nil
+}
+
+// Run implements all the necessary functionality for the orphans command.
+func (o *CmdOptions) Run() error {
+	ctx := context.Background()
+
+	// First check if Kubernetes cluster is reachable
+	if err := o.Client.IsReachable(); err != nil {
+		return err
+	}
+
+	// Fetch resources in the cluster
+	isClusterScopeRequest := o.Flags.AllNamespaces != nil && *o.Flags.AllNamespaces
+	objs, _, err := lineageutil.ListObjects(ctx, o.Client, lineageutil.ListOptions{
+		Namespace:     o.Namespace,
+		AllNamespaces: isClusterScopeRequest,
+		Scopes:        *o.Flags.Scopes,
+		ExcludeTypes:  *o.Flags.ExcludeTypes,
+		IncludeTypes:  *o.Flags.IncludeTypes,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Resolve the relationships between all objects & find objects without any
+	// relationships
+	nodeMap, err := graph.ResolveGraph(o.Client.GetMapper(), objs, graph.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	orphans, nsSet := graph.NodeMap{}, map[string]struct{}{}
+	for uid, node := range nodeMap {
+		if len(node.Dependencies) == 0 && len(node.Dependents) == 0 {
+			orphans[uid] = node
+			nsSet[node.Namespace] = struct{}{}
+		}
+	}
+	if len(orphans) == 0 {
+		if isClusterScopeRequest {
+			fmt.Fprintln(o.ErrOut, "No orphaned objects found.")
+		} else {
+			fmt.Fprintf(o.ErrOut, "No orphaned objects found in %s namespace.\n", o.Namespace)
+		}
+		return nil
+	}
+
+	// Print output
+	showNamespace := len(nsSet) > 1 || isClusterScopeRequest
+	return lineageprinters.PrintOrphans(o.Out, orphans, *o.Flags.ShowGroup, showNamespace, *o.Flags.NoHeaders)
+}
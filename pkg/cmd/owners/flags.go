@@ -0,0 +1,49 @@
+package owners
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagFromFiles          = "from-file"
+	flagFromFilesShorthand = "f"
+	flagShowGroup          = "show-group"
+)
+
+// Flags composes common configuration flag structs used in the command.
+type Flags struct {
+	FromFiles *[]string
+	ShowGroup *bool
+}
+
+// Copy returns a copy of Flags for mutation.
+func (f *Flags) Copy() Flags {
+	Flags := *f
+	return Flags
+}
+
+// AddFlags receives a *pflag.FlagSet reference and binds flags related to
+// configuration to it.
+func (f *Flags) AddFlags(flags *pflag.FlagSet) {
+	if f.FromFiles != nil {
+		usage := fmt.Sprintf("Accepts a comma separated list of files, directories or tar archives containing objects to find owners in, instead of fetching objects from the cluster. You can also use multiple flag options like -%s file1 -%s file2...", flagFromFilesShorthand, flagFromFilesShorthand)
+		flags.StringSliceVarP(f.FromFiles, flagFromFiles, flagFromFilesShorthand, *f.FromFiles, usage)
+	}
+	if f.ShowGroup != nil {
+		flags.BoolVar(f.ShowGroup, flagShowGroup, *f.ShowGroup, "If present, include the resource group for the objects in each owner chain")
+	}
+}
+
+// NewFlags returns flags associated with command configuration, with default
+// values set.
+func NewFlags() *Flags {
+	fromFiles := []string{}
+	showGroup := false
+
+	return &Flags{
+		FromFiles: &fromFiles,
+		ShowGroup: &showGroup,
+	}
+}
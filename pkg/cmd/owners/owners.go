@@ -0,0 +1,210 @@
+package owners
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/tohjustin/kube-lineage/internal/client"
+	"github.com/tohjustin/kube-lineage/internal/graph"
+	"github.com/tohjustin/kube-lineage/internal/log"
+	lineageprinters "github.com/tohjustin/kube-lineage/internal/printers"
+)
+
+var (
+	cmdPath    string
+	cmdName    = "owners"
+	cmdUse     = "%CMD% TYPE[.VERSION][.GROUP]/NAME [flags]"
+	cmdExample = templates.Examples(`
+		# List the owner chains of the pod named "foo"
+		%CMD_PATH% pod/foo
+
+		# List the owner chains of the job named "bar" in namespace "baz"
+		%CMD_PATH% job/bar --namespace=baz
+
+		# List the owner chains of the pod named "foo" using objects from a cluster dump
+		%CMD_PATH% pod/foo --from-file=cluster-dump.tar.gz`)
+	cmdShort = "Display the owner chains of a Kubernetes object up to its top-level owners"
+	cmdLong  = templates.LongDesc(`
+		Display the owner chains of a Kubernetes object up to its top-level owners.
+
+		Owner references are walked upwards until reaching objects without any
+		owner. Each chain is printed on a single line, starting from a top-level
+		owner, where an arrow points from an owner towards the object it owns.
+
+		TYPE is a Kubernetes resource. Shortcuts and groups will be resolved.
+		NAME is the name of a particular Kubernetes resource.`)
+)
+
+// CmdOptions contains all the options for running the owners command.
+type CmdOptions struct {
+	// RequestObject represents the object to find the owners of, in the
+	// <resource>/<name> form.
+	RequestObject string
+	Flags         *Flags
+
+	Namespace   string
+	Client      client.Interface
+	ClientFlags *client.Flags
+
+	genericclioptions.IOStreams
+}
+
+// NewCmd returns an initialized Command for the owners command.
+func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *cobra.Command {
+	o := &CmdOptions{
+		Flags:       NewFlags(),
+		ClientFlags: client.NewFlags(),
+		IOStreams:   streams,
+	}
+
+	f := cmdutil.NewFactory(o.ClientFlags)
+	util.SetFactoryForCompletion(f)
+
+	if len(name) > 0 {
+		cmdName = name
+	}
+	cmdPath = cmdName
+	if len(parentCmdPath) > 0 {
+		cmdPath = parentCmdPath + " " + cmdName
+	}
+	cmd := &cobra.Command{
+		Use:                   strings.ReplaceAll(cmdUse, "%CMD%", cmdName),
+		Example:               strings.ReplaceAll(cmdExample, "%CMD_PATH%", cmdPath),
+		Short:                 cmdShort,
+		Long:                  cmdLong,
+		Args:                  cobra.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+		DisableSuggestions:    true,
+		SilenceUsage:          true,
+		Run: func(c *cobra.Command, args []string) {
+			klog.V(4).Infof("Version: %s", c.Root().Version)
+			cmdutil.CheckErr(o.Complete(c, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) >= 1 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return compGetResourceList(o, f, cmd, toComplete)
+		},
+	}
+
+	// Setup flags
+	o.Flags.AddFlags(cmd.Flags())
+	o.ClientFlags.AddFlags(cmd.Flags())
+	log.AddFlags(cmd.Flags())
+
+	// Setup flag completion function
+	o.ClientFlags.RegisterFlagCompletionFunc(cmd, f)
+
+	return cmd
+}
+
+// Complete completes all the required options for the owners command.
+func (o *CmdOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) == 1 {
+		o.RequestObject = args[0]
+	}
+
+	// Setup client
+	o.Namespace, _, err = o.ClientFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	if o.Flags.FromFiles != nil && len(*o.Flags.FromFiles) > 0 {
+		o.Client, err = client.NewFromFiles(*o.Flags.FromFiles, o.Namespace)
+	} else {
+		o.Client, err = o.ClientFlags.ToClient()
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates all the required options for the owners command.
+func (o *CmdOptions) Validate() error {
+	if len(o.RequestObject) == 0 {
+		return fmt.Errorf("a resource must be specified as <resource>/<name>\nSee '%s -h' for help and examples", cmdPath)
+	}
+	if tokens := strings.SplitN(o.RequestObject, "/", 2); len(tokens) != 2 || len(tokens[0]) == 0 || len(tokens[1]) == 0 {
+		return fmt.Errorf("arguments in <resource>/<name> form must have a single resource and name\nSee '%s -h' for help and examples", cmdPath)
+	}
+
+	klog.V(4).Infof("Namespace: %s", o.Namespace)
+	klog.V(4).Infof("RequestObject: %v", o.RequestObject)
+	klog.V(4).Infof("Flags.FromFiles: %v", *o.Flags.FromFiles)
+	klog.V(4).Infof("Flags.ShowGroup: %t", *o.Flags.ShowGroup)
+	klog.V(4).Infof("ClientFlags.Context: %s", *o.ClientFlags.Context)
+	klog.V(4).Infof("ClientFlags.Namespace: %s", *o.ClientFlags.Namespace)
+
+	return nil
+}
+
+// Run implements all the necessary functionality for the owners command.
+func (o *CmdOptions) Run() error {
+	ctx := context.Background()
+
+	// First check if Kubernetes cluster is reachable
+	if err := o.Client.IsReachable(); err != nil {
+		return err
+	}
+
+	// Fetch the provided object to ensure it exists before proceeding
+	obj, err := o.getObject(ctx, o.RequestObject)
+	if err != nil {
+		return err
+	}
+
+	// Fetch resources in the cluster, owners are either cluster-scoped or in the
+	// same namespace as the object they own
+	objs, err := o.Client.List(ctx, client.ListOptions{Namespaces: []string{o.Namespace}})
+	if err != nil {
+		return err
+	}
+
+	// Include requested object into objects to handle cases where user has
+	// access to get the requested object but unable to list its resource type
+	objs.Items = append(objs.Items, *obj)
+
+	// Find all owner chains of the requested object
+	nodeMap, err := graph.ResolveGraph(o.Client.GetMapper(), objs.Items, graph.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	paths := graph.FindOwnerPaths(nodeMap, obj.GetUID())
+	if len(paths) == 0 {
+		fmt.Fprintf(o.ErrOut, "No owners found for \"%s\".\n", o.RequestObject)
+		return nil
+	}
+
+	// Print output
+	return lineageprinters.PrintPaths(o.Out, paths, o.Namespace, *o.Flags.ShowGroup)
+}
+
+// getObject fetches the object of the provided <resource>/<name> string in the
+// current namespace.
+func (o *CmdOptions) getObject(ctx context.Context, s string) (*unstructuredv1.Unstructured, error) {
+	tokens := strings.SplitN(s, "/", 2)
+	api, err := o.Client.ResolveAPIResource(tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	return o.Client.Get(ctx, tokens[1], client.GetOptions{
+		APIResource: *api,
+		Namespace:   o.Namespace,
+	})
+}
@@ -0,0 +1,238 @@
+package path
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/tohjustin/kube-lineage/internal/client"
+	"github.com/tohjustin/kube-lineage/internal/graph"
+	"github.com/tohjustin/kube-lineage/internal/log"
+	lineageprinters "github.com/tohjustin/kube-lineage/internal/printers"
+	lineageutil "github.com/tohjustin/kube-lineage/pkg/cmd/util"
+)
+
+var (
+	cmdPath    string
+	cmdName    = "path"
+	cmdUse     = "%CMD% TYPE[.VERSION][.GROUP]/NAME TYPE[.VERSION][.GROUP]/NAME [flags]"
+	cmdExample = templates.Examples(`
+		# List the shortest relationship paths between the secret named "foo" & the ingress named "bar"
+		%CMD_PATH% secret/foo ingress/bar
+
+		# List the shortest relationship paths between the clusterrole named "foo" & the pod named "bar" in namespace "baz"
+		%CMD_PATH% clusterrole/foo pod/bar --namespace=baz
+
+		# List the shortest relationship paths between two objects using objects from a cluster dump
+		%CMD_PATH% secret/foo ingress/bar --from-file=cluster-dump.tar.gz`)
+	cmdShort = "Display the shortest relationship paths between two Kubernetes objects"
+	cmdLong  = templates.LongDesc(`
+		Display the shortest relationship paths between two Kubernetes objects.
+
+		Each path is printed on a single line, where an arrow points from an object
+		towards its dependent. Relationships are traversed in both directions, so a
+		path may consist of both dependencies & dependents.
+
+		TYPE is a Kubernetes resource. Shortcuts and groups will be resolved.
+		NAME is the name of a particular Kubernetes resource.`)
+)
+
+// CmdOptions contains all the options for running the path command.
+type CmdOptions struct {
+	// RequestFrom represents the object where the paths start from, in the
+	// <resource>/<name> form.
+	RequestFrom string
+	// RequestTo represents the object where the paths end at, in the
+	// <resource>/<name> form.
+	RequestTo string
+	Flags     *Flags
+
+	Namespace   string
+	Client      client.Interface
+	ClientFlags *client.Flags
+	GraphFlags  *lineageutil.GraphFlags
+
+	genericclioptions.IOStreams
+}
+
+// NewCmd returns an initialized Command for the path command.
+func NewCmd(streams genericclioptions.IOStreams, name, parentCmdPath string) *cobra.Command {
+	o := &CmdOptions{
+		Flags:       NewFlags(),
+		ClientFlags: client.NewFlags(),
+		GraphFlags:  lineageutil.NewGraphFlags(),
+		IOStreams:   streams,
+	}
+
+	f := cmdutil.NewFactory(o.ClientFlags)
+	util.SetFactoryForCompletion(f)
+
+	if len(name) > 0 {
+		cmdName = name
+	}
+	cmdPath = cmdName
+	if len(parentCmdPath) > 0 {
+		cmdPath = parentCmdPath + " " + cmdName
+	}
+	cmd := &cobra.Command{
+		Use:                   strings.ReplaceAll(cmdUse, "%CMD%", cmdName),
+		Example:               strings.ReplaceAll(cmdExample, "%CMD_PATH%", cmdPath),
+		Short:                 cmdShort,
+		Long:                  cmdLong,
+		Args:                  cobra.MaximumNArgs(2),
+		DisableFlagsInUseLine: true,
+		DisableSuggestions:    true,
+		SilenceUsage:          true,
+		Run: func(c *cobra.Command, args []string) {
+			klog.V(4).Infof("Version: %s", c.Root().Version)
+			cmdutil.CheckErr(o.Complete(c, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) >= 2 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return compGetResourceList(o, f, cmd, toComplete)
+		},
+	}
+
+	// Setup flags
+	o.Flags.AddFlags(cmd.Flags())
+	o.ClientFlags.AddFlags(cmd.Flags())
+	o.GraphFlags.AddFlags(cmd.Flags())
+	log.AddFlags(cmd.Flags())
+
+	// Setup flag completion function
+	o.Flags.RegisterFlagCompletionFunc(cmd, f)
+	o.ClientFlags.RegisterFlagCompletionFunc(cmd, f)
+
+	return cmd
+}
+
+// Complete completes all the required options for the path command.
+func (o *CmdOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) == 2 {
+		o.RequestFrom = args[0]
+		o.RequestTo = args[1]
+	}
+
+	// Register relationship rules & setup client
+	o.Client, o.Namespace, err = o.GraphFlags.Complete(o.ClientFlags)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates all the required options for the path command.
+func (o *CmdOptions) Validate() error {
+	if len(o.RequestFrom) == 0 || len(o.RequestTo) == 0 {
+		return fmt.Errorf("two resources must be specified as <resource>/<name> <resource>/<name>\nSee '%s -h' for help and examples", cmdPath)
+	}
+	for _, r := range []string{o.RequestFrom, o.RequestTo} {
+		if tokens := strings.SplitN(r, "/", 2); len(tokens) != 2 || len(tokens[0]) == 0 || len(tokens[1]) == 0 {
+			return fmt.Errorf("arguments in <resource>/<name> form must have a single resource and name\nSee '%s -h' for help and examples", cmdPath)
+		}
+	}
+
+	klog.V(4).Infof("Namespace: %s", o.Namespace)
+	klog.V(4).Infof("RequestFrom: %v", o.RequestFrom)
+	klog.V(4).Infof("RequestTo: %v", o.RequestTo)
+	klog.V(4).Infof("Flags.AllNamespaces: %t", *o.Flags.AllNamespaces)
+	klog.V(4).Infof("Flags.ExcludeTypes: %v", *o.Flags.ExcludeTypes)
+	klog.V(4).Infof("Flags.IncludeTypes: %v", *o.Flags.IncludeTypes)
+	klog.V(4).Infof("Flags.MaxPaths: %d", *o.Flags.MaxPaths)
+	klog.V(4).Infof("Flags.Scopes: %v", *o.Flags.Scopes)
+	klog.V(4).Infof("Flags.ShowGroup: %t", *o.Flags.ShowGroup)
+	klog.V(4).Infof("ClientFlags.Context: %s", *o.ClientFlags.Context)
+	klog.V(4).Infof("ClientFlags.Namespace: %s", *o.ClientFlags.Namespace)
+	klog.V(4).Infof("GraphFlags.FromFiles: %v", *o.GraphFlags.FromFiles)
+	klog.V(4).Infof("GraphFlags.RelationshipRules: %s", *o.GraphFlags.RelationshipRules)
+
+	return nil
+}
+
+// Run implements all the necessary functionality for the path command.
+func (o *CmdOptions) Run() error {
+	ctx := context.Background()
+
+	// First check if Kubernetes cluster is reachable
+	if err := o.Client.IsReachable(); err != nil {
+		return err
+	}
+
+	// Fetch the provided objects to ensure they exist before proceeding
+	from, err := o.getObject(ctx, o.RequestFrom)
+	if err != nil {
+		return err
+	}
+	to, err := o.getObject(ctx, o.RequestTo)
+	if err != nil {
+		return err
+	}
+
+	// Fetch resources in the cluster
+	objs, _, err := lineageutil.ListObjects(ctx, o.Client, lineageutil.ListOptions{
+		Namespace:     o.Namespace,
+		AllNamespaces: *o.Flags.AllNamespaces,
+		Scopes:        *o.Flags.Scopes,
+		ExcludeTypes:  *o.Flags.ExcludeTypes,
+		IncludeTypes:  *o.Flags.IncludeTypes,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Include requested objects into objects to handle cases where user has
+	// access to get the requested objects but unable to list their resource type
+	objs = append(objs, *from, *to)
+
+	// Find all shortest paths between the requested objects
+	nodeMap, err := graph.ResolveGraph(o.Client.GetMapper(), objs, graph.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	maxPaths := *o.Flags.MaxPaths
+	limit := maxPaths
+	if limit > 0 {
+		// Find an extra path to determine if any path is left out
+		limit++
+	}
+	paths := graph.FindShortestPaths(nodeMap, from.GetUID(), to.GetUID(), limit)
+	if len(paths) == 0 {
+		return fmt.Errorf("no relationship path found between \"%s\" & \"%s\"", o.RequestFrom, o.RequestTo)
+	}
+	if maxPaths > 0 && uint(len(paths)) > maxPaths {
+		paths = paths[:maxPaths]
+		fmt.Fprintf(o.ErrOut, "Only the first %d paths are displayed, use --%s to display more.\n", maxPaths, flagMaxPaths)
+	}
+
+	// Print output
+	return lineageprinters.PrintPaths(o.Out, paths, o.Namespace, *o.Flags.ShowGroup)
+}
+
+// getObject fetches the object of the provided <resource>/<name> string in the
+// current namespace.
+func (o *CmdOptions) getObject(ctx context.Context, s string) (*unstructuredv1.Unstructured, error) {
+	tokens := strings.SplitN(s, "/", 2)
+	api, err := o.Client.ResolveAPIResource(tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	return o.Client.Get(ctx, tokens[1], client.GetOptions{
+		APIResource: *api,
+		Namespace:   o.Namespace,
+	})
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tohjustin/kube-lineage/internal/client"
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+const (
+	flagFromFiles          = "from-file"
+	flagFromFilesShorthand = "f"
+	flagRelationshipRules  = "relationship-rules"
+)
+
+// GraphFlags composes common graph configuration flag structs used in the
+// command.
+type GraphFlags struct {
+	FromFiles         *[]string
+	RelationshipRules *string
+}
+
+// Copy returns a copy of GraphFlags for mutation.
+func (f *GraphFlags) Copy() GraphFlags {
+	GraphFlags := *f
+	return GraphFlags
+}
+
+// AddFlags receives a *pflag.FlagSet reference and binds flags related to graph
+// configuration to it.
+func (f *GraphFlags) AddFlags(flags *pflag.FlagSet) {
+	if f.FromFiles != nil {
+		usage := fmt.Sprintf("Accepts a comma separated list of files, directories or tar archives containing objects to use, instead of fetching objects from the cluster. You can also use multiple flag options like -%s file1 -%s file2...", flagFromFilesShorthand, flagFromFilesShorthand)
+		flags.StringSliceVarP(f.FromFiles, flagFromFiles, flagFromFilesShorthand, *f.FromFiles, usage)
+	}
+	if f.RelationshipRules != nil {
+		flags.StringVar(f.RelationshipRules, flagRelationshipRules, *f.RelationshipRules, "Path to a YAML file containing rules to discover relationships of additional resource types (default \"~/.kube-lineage/rules.yaml\")")
+	}
+}
+
+// LoadRelationshipRules registers the relationship rules from the file of the
+// flag configuration.
+func (f *GraphFlags) LoadRelationshipRules() error {
+	var path string
+	if f.RelationshipRules != nil {
+		path = *f.RelationshipRules
+	}
+	return graph.LoadRelationshipRules(path)
+}
+
+// ToClient returns a client reading objects from the files of the flag
+// configuration if any is set, otherwise a client based on the provided client
+// flag configuration.
+func (f *GraphFlags) ToClient(clientFlags *client.Flags, namespace string) (client.Interface, error) {
+	if f.FromFiles != nil && len(*f.FromFiles) > 0 {
+		return client.NewFromFiles(*f.FromFiles, namespace)
+	}
+	return clientFlags.ToClient()
+}
+
+// Complete registers the relationship rules of the flag configuration & returns
+// the current namespace along with the client to fetch objects with.
+func (f *GraphFlags) Complete(clientFlags *client.Flags) (client.Interface, string, error) {
+	if err := f.LoadRelationshipRules(); err != nil {
+		return nil, "", err
+	}
+	namespace, _, err := clientFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := f.ToClient(clientFlags, namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	return c, namespace, nil
+}
+
+// NewGraphFlags returns flags associated with graph configuration, with default
+// values set.
+func NewGraphFlags() *GraphFlags {
+	fromFiles := []string{}
+	relationshipRules := ""
+
+	return &GraphFlags{
+		FromFiles:         &fromFiles,
+		RelationshipRules: &relationshipRules,
+	}
+}
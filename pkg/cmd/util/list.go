@@ -0,0 +1,80 @@
+package util
+
+import (
+	"context"
+
+	unstructuredv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/tohjustin/kube-lineage/internal/client"
+)
+
+// ListOptions contains the options of a command for determining the objects to
+// list.
+type ListOptions struct {
+	// Namespace is the current namespace.
+	Namespace string
+	// AllNamespaces lists objects across all namespaces, including
+	// cluster-scoped objects.
+	AllNamespaces bool
+	// Scopes are the additional namespaces to list objects in.
+	Scopes []string
+	// ExcludeTypes are the resource types to exclude from listing.
+	ExcludeTypes []string
+	// IncludeTypes are the only resource types to list, if any is set.
+	IncludeTypes []string
+}
+
+// Namespaces returns the namespaces to list objects in, an empty namespace
+// represents all namespaces.
+func (o *ListOptions) Namespaces() []string {
+	namespaces := []string{o.Namespace}
+	if o.AllNamespaces {
+		namespaces = append(namespaces, "")
+	}
+	namespaces = append(namespaces, o.Scopes...)
+	return namespaces
+}
+
+// ToClientListOptions resolves the included & excluded resource types of the
+// options into the client options for listing objects.
+func (o *ListOptions) ToClientListOptions(c client.Interface) (client.ListOptions, error) {
+	excludeAPIs, err := resolveAPIResources(c, o.ExcludeTypes)
+	if err != nil {
+		return client.ListOptions{}, err
+	}
+	includeAPIs, err := resolveAPIResources(c, o.IncludeTypes)
+	if err != nil {
+		return client.ListOptions{}, err
+	}
+	return client.ListOptions{
+		APIResourcesToExclude: excludeAPIs,
+		APIResourcesToInclude: includeAPIs,
+		Namespaces:            o.Namespaces(),
+	}, nil
+}
+
+// ListObjects lists the objects determined by the provided options, it also
+// returns the resolved client options used for listing them.
+func ListObjects(ctx context.Context, c client.Interface, o ListOptions) ([]unstructuredv1.Unstructured, client.ListOptions, error) {
+	opts, err := o.ToClientListOptions(c)
+	if err != nil {
+		return nil, opts, err
+	}
+	objs, err := c.List(ctx, opts)
+	if err != nil {
+		return nil, opts, err
+	}
+	return objs.Items, opts, nil
+}
+
+func resolveAPIResources(c client.Interface, kinds []string) ([]client.APIResource, error) {
+	apis := []client.APIResource{}
+	for _, kind := range kinds {
+		api, err := c.ResolveAPIResource(kind)
+		if err != nil {
+			return nil, err
+		}
+		apis = append(apis, *api)
+	}
+	return apis, nil
+}
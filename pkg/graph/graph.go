@@ -0,0 +1,75 @@
+// Package graph exposes the relationship resolver registry used to build the
+// relationship graph, so that other tools can register relationship resolvers
+// for their own resource types (eg. CustomResourceDefinitions).
+package graph
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/tohjustin/kube-lineage/internal/graph"
+)
+
+type (
+	// Node represents a Kubernetes object in an relationship tree.
+	Node = graph.Node
+	// ObjectLabelSelector is a reference to a collection of Kubernetes objects.
+	ObjectLabelSelector = graph.ObjectLabelSelector
+	// ObjectReference is a reference to a Kubernetes object.
+	ObjectReference = graph.ObjectReference
+	// ObjectReferenceKey is a compact representation of an ObjectReference.
+	ObjectReferenceKey = graph.ObjectReferenceKey
+	// ObjectRuleSelector is a reference to a collection of Kubernetes objects,
+	// matched the same way admission webhook rules & RBAC policy rules match
+	// objects.
+	ObjectRuleSelector = graph.ObjectRuleSelector
+	// ObjectSelector is a reference to a collection of Kubernetes objects.
+	ObjectSelector = graph.ObjectSelector
+	// Relationship represents a relationship type between two Kubernetes
+	// objects.
+	Relationship = graph.Relationship
+	// RelationshipMap contains a map of relationships a Kubernetes object has
+	// with other objects in the cluster.
+	RelationshipMap = graph.RelationshipMap
+	// RelationshipRule declares a relationship between objects of a resource
+	// type & the objects referenced by one of their fields.
+	RelationshipRule = graph.RelationshipRule
+	// RelationshipRuleTarget describes the objects referenced by a relationship
+	// rule.
+	RelationshipRuleTarget = graph.RelationshipRuleTarget
+	// RelationshipResolver returns a map of relationships that the provided
+	// object has with other objects.
+	RelationshipResolver = graph.RelationshipResolver
+	// PostResolveHook updates the relationships of the objects in the provided
+	// node map once the relationships of every object are resolved.
+	PostResolveHook = graph.PostResolveHook
+)
+
+// NewRelationshipMap returns an empty RelationshipMap.
+func NewRelationshipMap() RelationshipMap {
+	return graph.NewRelationshipMap()
+}
+
+// RegisterResolver registers the relationship resolver for objects of the
+// provided GroupKind, replacing any resolver previously registered for it.
+func RegisterResolver(gk schema.GroupKind, r RelationshipResolver) {
+	graph.RegisterResolver(gk, r)
+}
+
+// GetResolver returns the relationship resolver registered for objects of the
+// provided GroupKind, combined with the relationship rules registered for it.
+func GetResolver(gk schema.GroupKind) (RelationshipResolver, bool) {
+	return graph.GetResolver(gk)
+}
+
+// RegisterPostResolveHook registers the provided post-resolve hook, which is run
+// after the hooks previously registered.
+func RegisterPostResolveHook(h PostResolveHook) {
+	graph.RegisterPostResolveHook(h)
+}
+
+// RegisterRelationshipRules registers the provided relationship rules, on top
+// of any resolver registered for the same resource type. Rules previously
+// registered for the same resource type are replaced.
+func RegisterRelationshipRules(rules []RelationshipRule) error {
+	return graph.RegisterRelationshipRules(rules)
+}